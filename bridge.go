@@ -0,0 +1,662 @@
+package sirkeji
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BridgePathHeader is the header listing, comma separated, the UIDs of the Bridges an
+// event was forwarded by.
+const BridgePathHeader = "bridge-path"
+
+// DefaultMaxFrameSize bounds the frames exchanged by Bridges created without WithMaxFrameSize.
+const DefaultMaxFrameSize = 16 << 20
+
+// DefaultHandshakeTimeout bounds the handshake of the peers of Bridges created without
+// WithHandshakeTimeout.
+const DefaultHandshakeTimeout = 10 * time.Second
+
+// DefaultBridgeQueueSize is the number of events queued for every peer of a Bridge
+// created without WithBridgeQueueSize.
+const DefaultBridgeQueueSize = 256
+
+var (
+	// ErrBridgeClosed is returned when listening or connecting with a closed Bridge.
+	ErrBridgeClosed = errors.New("bridge closed")
+
+	// ErrFrameTooLarge is returned when a Bridge receives a frame above its maximum frame size.
+	ErrFrameTooLarge = errors.New("bridge frame too large")
+
+	// ErrBridgeHandshake is returned when a peer doesn't introduce itself properly,
+	// e.g. when it has the same UID as the Bridge.
+	ErrBridgeHandshake = errors.New("bridge handshake failed")
+)
+
+// Bridge links the Streamers of several processes over TCP or Unix sockets.
+//
+// Every Bridge subscribes to its local Streamer and forwards the events it receives to
+// the peers it is connected to, which publish them to their own Streamer. Bridges accept
+// peers with Listen and connect to peers with Connect, reconnecting after failures.
+//
+// Events are exchanged as length-prefixed frames: a 4-byte big-endian length followed by
+// the JSON encoded event, its payload being encoded with the Codec of its EventType
+// (see WithCodec). Events with a payload but no Codec are skipped silently, events
+// whose payload fails to encode are logged and aren't forwarded either.
+//
+// Events received from a peer carry the BridgePathHeader and aren't forwarded again,
+// unless the Bridge relays events with WithRelay.
+type Bridge struct {
+	// streamer is the local Streamer events are forwarded from and published to.
+	streamer Streamer
+	// uid identifies the Bridge's subscription and the Bridge to its peers.
+	uid string
+	// forward holds the patterns of the EventTypes forwarded to peers, all but Shutdown if empty.
+	forward []EventType
+	// accepted holds the patterns of the EventTypes accepted from peers, all if empty.
+	accepted []EventType
+	// relay forwards the events received from peers to the other peers.
+	relay bool
+	// backoff decides the delay between reconnection attempts.
+	backoff RetryPolicy
+	// maxFrameSize bounds the size of the frames received from peers.
+	maxFrameSize int
+	// queueSize is the number of events queued for every peer.
+	queueSize int
+	// handshakeTimeout bounds the time peers take to introduce themselves.
+	handshakeTimeout time.Duration
+
+	// peers holds the connected peers by UID.
+	peers map[string]*bridgePeer
+	// conns holds the open connections, including those whose handshake isn't over.
+	conns map[net.Conn]struct{}
+	// listeners holds the listeners accepting peers.
+	listeners []net.Listener
+	// ctx is cancelled when the Bridge is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg waits for the goroutines of the Bridge.
+	wg sync.WaitGroup
+	mu sync.Mutex
+}
+
+// bridgePeer is a connection to a peer Bridge.
+type bridgePeer struct {
+	uid  string
+	conn net.Conn
+	// out queues the encoded frames written to the peer.
+	out chan []byte
+	// closed is closed once the connection is closed.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// close closes the connection to the peer.
+func (p *bridgePeer) close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		_ = p.conn.Close()
+	})
+}
+
+// bridgeFrame is a single message exchanged between Bridges.
+type bridgeFrame struct {
+	// Hello introduces a Bridge by its UID, it is the first frame sent on a connection.
+	Hello string `json:"hello,omitempty"`
+	// Event is a forwarded event.
+	Event *eventEnvelope `json:"event,omitempty"`
+}
+
+// BridgeOption configures a Bridge created by NewBridge.
+type BridgeOption func(*Bridge)
+
+// WithForwardTypes restricts the events forwarded to peers to the given EventTypes,
+// which may be patterns like "orders.>" (see MatchEventType).
+//
+// Without this option every event but Shutdown events is forwarded, so shutting down a
+// process doesn't shut down its peers.
+//
+// Example:
+//
+//	bridge, err := NewBridge(streamer, "orders-bridge", WithForwardTypes("orders.>", Error))
+func WithForwardTypes(types ...EventType) BridgeOption {
+	return func(b *Bridge) {
+		b.forward = types
+	}
+}
+
+// WithAcceptTypes restricts the events published from peers to the given EventTypes,
+// which may be patterns like "orders.>" (see MatchEventType). Other events are dropped.
+//
+// Without this option every event received from peers is published.
+//
+// Example:
+//
+//	bridge, err := NewBridge(streamer, "billing-bridge", WithAcceptTypes("orders.created"))
+func WithAcceptTypes(types ...EventType) BridgeOption {
+	return func(b *Bridge) {
+		b.accepted = types
+	}
+}
+
+// WithRelay makes the Bridge forward the events received from a peer to its other peers,
+// so processes can be linked in a chain or a star rather than each to each.
+//
+// Every Bridge adds its UID to the BridgePathHeader of the events it forwards, events
+// are never forwarded to a peer already on their path and are dropped by Bridges already
+// on their path, so relayed events can't loop.
+//
+// Example:
+//
+//	hub, err := NewBridge(streamer, "hub", WithRelay())
+func WithRelay() BridgeOption {
+	return func(b *Bridge) {
+		b.relay = true
+	}
+}
+
+// WithReconnectBackoff sets the RetryPolicy deciding the delay between attempts to
+// reconnect to peers, the attempts being counted from the last successful connection.
+// Connect gives up once the policy stops retrying.
+//
+// Without this option the Bridge retries forever, starting after 100ms and doubling the
+// delay up to 30s.
+//
+// Example:
+//
+//	bridge, err := NewBridge(streamer, "edge", WithReconnectBackoff(FixedRetry(10, time.Second)))
+func WithReconnectBackoff(policy RetryPolicy) BridgeOption {
+	return func(b *Bridge) {
+		b.backoff = policy
+	}
+}
+
+// WithMaxFrameSize bounds the size of the frames received from peers, connections
+// sending larger frames are closed. DefaultMaxFrameSize is used without this option.
+func WithMaxFrameSize(size int) BridgeOption {
+	return func(b *Bridge) {
+		b.maxFrameSize = size
+	}
+}
+
+// WithHandshakeTimeout bounds the time peers take to introduce themselves once connected,
+// connections whose handshake isn't over in time are closed. DefaultHandshakeTimeout is
+// used without this option.
+func WithHandshakeTimeout(timeout time.Duration) BridgeOption {
+	return func(b *Bridge) {
+		b.handshakeTimeout = timeout
+	}
+}
+
+// WithBridgeQueueSize sets the number of events queued for every peer, events forwarded
+// to a peer whose queue is full are dropped. DefaultBridgeQueueSize is used without this option.
+func WithBridgeQueueSize(size int) BridgeOption {
+	return func(b *Bridge) {
+		b.queueSize = size
+	}
+}
+
+// NewBridge creates a Bridge and subscribes it to the Streamer.
+//
+// Parameters:
+//   - streamer: The local Streamer. Must not be nil.
+//   - uid: The unique identifier of the Bridge, used for its subscription and by its
+//     peers. Bridges linked together must have different UIDs.
+//   - opts: Optional BridgeOptions.
+//
+// Returns:
+//   - A pointer to a new Bridge, which still has to Listen or Connect to peers.
+//   - An error if the streamer is nil or the Bridge couldn't be subscribed (e.g., duplicate UID).
+//
+// Example:
+//
+//	bridge, err := NewBridge(streamer, "orders-service")
+//	if err != nil {
+//	    log.Fatalf("failed to create bridge: %v", err)
+//	}
+//	defer bridge.Close()
+//
+//	if _, err := bridge.Listen("tcp", ":7070"); err != nil {
+//	    log.Fatalf("failed to listen: %v", err)
+//	}
+//	_ = bridge.Connect("unix", "/run/billing/sirkeji.sock")
+func NewBridge(streamer Streamer, uid string, opts ...BridgeOption) (*Bridge, error) {
+	if streamer == nil {
+		return nil, ErrStreamerShouldNotBeNil
+	}
+
+	b := &Bridge{
+		streamer:         streamer,
+		uid:              uid,
+		backoff:          ExponentialRetry(math.MaxInt, 100*time.Millisecond, 30*time.Second, 0.2),
+		maxFrameSize:     DefaultMaxFrameSize,
+		queueSize:        DefaultBridgeQueueSize,
+		handshakeTimeout: DefaultHandshakeTimeout,
+		peers:            make(map[string]*bridgePeer),
+		conns:            make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	var subscribeOpts []SubscribeOption
+	if len(b.forward) > 0 {
+		subscribeOpts = append(subscribeOpts, WithEventTypes(b.forward...))
+	}
+	ch, err := streamer.Subscribe(uid, subscribeOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.wg.Add(1)
+	go b.dispatch(ch)
+
+	return b, nil
+}
+
+// Listen accepts peers on the given network address.
+//
+// Parameters:
+//   - network: "tcp", "tcp4", "tcp6" or "unix".
+//   - address: The address to listen on, e.g. ":7070" or "/run/orders/sirkeji.sock".
+//
+// Returns:
+//   - The address the Bridge listens on, useful with port 0.
+//   - ErrBridgeClosed if the Bridge was closed, or the error returned by net.Listen.
+//
+// Example:
+//
+//	addr, err := bridge.Listen("tcp", "127.0.0.1:0")
+func (b *Bridge) Listen(network, address string) (net.Addr, error) {
+	if b.ctx.Err() != nil {
+		return nil, ErrBridgeClosed
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		_ = listener.Close()
+		return nil, ErrBridgeClosed
+	}
+	b.listeners = append(b.listeners, listener)
+	b.wg.Add(1)
+	b.mu.Unlock()
+
+	go b.accept(listener)
+	return listener.Addr(), nil
+}
+
+// accept serves the peers connecting to the listener until it is closed.
+func (b *Bridge) accept(listener net.Listener) {
+	defer b.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if b.ctx.Err() == nil {
+				log.Printf("[%s] stopped accepting peers: %v\n", b.uid, err)
+			}
+			return
+		}
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			if _, err := b.serve(conn); err != nil && b.ctx.Err() == nil {
+				log.Printf("[%s] peer %s disconnected: %v\n", b.uid, conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// Connect connects to the peer listening on the given network address.
+//
+// The connection is made in the background: Connect returns immediately, and the Bridge
+// reconnects whenever the connection fails, waiting between attempts as decided by its
+// reconnect backoff (see WithReconnectBackoff). Events forwarded while disconnected are
+// dropped.
+//
+// Parameters:
+//   - network: "tcp", "tcp4", "tcp6" or "unix".
+//   - address: The address of the peer, e.g. "billing:7070" or "/run/billing/sirkeji.sock".
+//
+// Returns:
+//   - ErrBridgeClosed if the Bridge was closed, nil otherwise.
+//
+// Example:
+//
+//	if err := bridge.Connect("tcp", "billing:7070"); err != nil {
+//	    log.Printf("failed to connect: %v", err)
+//	}
+func (b *Bridge) Connect(network, address string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx.Err() != nil {
+		return ErrBridgeClosed
+	}
+	b.wg.Add(1)
+	go b.connect(network, address)
+	return nil
+}
+
+// connect keeps a connection to the peer open until the Bridge is closed.
+func (b *Bridge) connect(network, address string) {
+	defer b.wg.Done()
+
+	var dialer net.Dialer
+	attempt := 0
+	for {
+		conn, err := dialer.DialContext(b.ctx, network, address)
+		if err == nil {
+			var connected bool
+			if connected, err = b.serve(conn); connected {
+				attempt = 0
+			}
+		}
+		if b.ctx.Err() != nil {
+			return
+		}
+
+		attempt++
+		delay, retry := b.backoff.Backoff(attempt, err)
+		if !retry {
+			log.Printf("[%s] gave up connecting to %s after %d attempts: %v\n", b.uid, address, attempt, err)
+			return
+		}
+		log.Printf("[%s] reconnecting to %s in %v: %v\n", b.uid, address, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// serve exchanges events with the peer on the connection until it fails or the Bridge
+// is closed. It reports whether the handshake succeeded.
+func (b *Bridge) serve(conn net.Conn) (bool, error) {
+	if err := b.track(conn); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	defer b.untrack(conn)
+
+	// Peers that never introduce themselves mustn't hold the connection forever.
+	_ = conn.SetDeadline(time.Now().Add(b.handshakeTimeout))
+	if err := b.writeFrame(conn, bridgeFrame{Hello: b.uid}); err != nil {
+		return false, err
+	}
+	var hello bridgeFrame
+	if err := b.readFrame(conn, &hello); err != nil {
+		return false, err
+	}
+	if hello.Hello == "" || hello.Hello == b.uid {
+		return false, fmt.Errorf("%w: peer introduced itself as %q", ErrBridgeHandshake, hello.Hello)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	peer := &bridgePeer{uid: hello.Hello, conn: conn, out: make(chan []byte, b.queueSize), closed: make(chan struct{})}
+	if err := b.add(peer); err != nil {
+		return false, err
+	}
+	defer b.remove(peer)
+
+	b.wg.Add(1)
+	go b.write(peer)
+	return true, b.read(peer)
+}
+
+// track records the open connection, so Close can close it.
+func (b *Bridge) track(conn net.Conn) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx.Err() != nil {
+		return ErrBridgeClosed
+	}
+	b.conns[conn] = struct{}{}
+	return nil
+}
+
+// untrack forgets the connection and closes it.
+func (b *Bridge) untrack(conn net.Conn) {
+	b.mu.Lock()
+	delete(b.conns, conn)
+	b.mu.Unlock()
+
+	_ = conn.Close()
+}
+
+// add registers the connected peer.
+func (b *Bridge) add(peer *bridgePeer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx.Err() != nil {
+		return ErrBridgeClosed
+	}
+	if _, exists := b.peers[peer.uid]; exists {
+		return fmt.Errorf("%w: %s is already connected", ErrBridgeHandshake, peer.uid)
+	}
+	b.peers[peer.uid] = peer
+	return nil
+}
+
+// remove unregisters the peer and closes its connection.
+func (b *Bridge) remove(peer *bridgePeer) {
+	b.mu.Lock()
+	if b.peers[peer.uid] == peer {
+		delete(b.peers, peer.uid)
+	}
+	b.mu.Unlock()
+
+	peer.close()
+}
+
+// write writes the frames queued for the peer until its connection is closed.
+func (b *Bridge) write(peer *bridgePeer) {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case frame := <-peer.out:
+			if _, err := peer.conn.Write(frame); err != nil {
+				peer.close()
+				return
+			}
+		case <-peer.closed:
+			return
+		}
+	}
+}
+
+// read publishes the events received from the peer until its connection fails.
+func (b *Bridge) read(peer *bridgePeer) error {
+	for {
+		var frame bridgeFrame
+		if err := b.readFrame(peer.conn, &frame); err != nil {
+			select {
+			case <-peer.closed:
+				return nil
+			default:
+				return err
+			}
+		}
+		if frame.Event == nil {
+			continue
+		}
+
+		event, err := frame.Event.event()
+		if err != nil {
+			log.Printf("[%s] dropped %s event from %s: %v\n", b.uid, frame.Event.Type, peer.uid, err)
+			continue
+		}
+		if !b.accepts(event) {
+			continue
+		}
+		_ = b.streamer.PublishContext(b.ctx, event)
+	}
+}
+
+// accepts reports whether an event received from a peer is published.
+func (b *Bridge) accepts(event Event) bool {
+	if slices.Contains(bridgePath(event), b.uid) {
+		return false
+	}
+	return len(b.accepted) == 0 || slices.ContainsFunc(b.accepted, func(pattern EventType) bool {
+		return MatchEventType(pattern, event.Type)
+	})
+}
+
+// dispatch queues the events of the subscription for the peers they are forwarded to.
+func (b *Bridge) dispatch(ch chan Event) {
+	defer b.wg.Done()
+
+	for event := range ch {
+		path := bridgePath(event)
+		if (len(path) > 0 && !b.relay) || (len(b.forward) == 0 && event.Type == Shutdown) {
+			continue
+		}
+		if event.Payload != nil && !HasPayloadCodec(event.Type) {
+			continue
+		}
+
+		frame, err := b.encode(event.WithHeader(BridgePathHeader, strings.Join(append(path, b.uid), ",")))
+		if err != nil {
+			log.Printf("[%s] didn't forward %s event %s: %v\n", b.uid, event.Type, event.ID, err)
+			continue
+		}
+
+		b.mu.Lock()
+		for uid, peer := range b.peers {
+			if slices.Contains(path, uid) {
+				continue
+			}
+			select {
+			case peer.out <- frame:
+			default:
+				log.Printf("[%s] dropped %s event %s for %s: queue full\n", b.uid, event.Type, event.ID, uid)
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// bridgePath returns the UIDs of the Bridges the event was forwarded by.
+func bridgePath(event Event) []string {
+	path := event.Header(BridgePathHeader)
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ",")
+}
+
+// encode returns the frame forwarding the event.
+func (b *Bridge) encode(event Event) ([]byte, error) {
+	env, err := newEventEnvelope(event)
+	if err != nil {
+		return nil, err
+	}
+	return encodeFrame(bridgeFrame{Event: &env})
+}
+
+// encodeFrame returns the length-prefixed JSON encoding of the frame.
+func encodeFrame(frame bridgeFrame) ([]byte, error) {
+	body, err := json.Marshal(frame)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 4+len(body))
+	binary.BigEndian.PutUint32(data, uint32(len(body)))
+	copy(data[4:], body)
+	return data, nil
+}
+
+// writeFrame writes a single frame to the connection.
+func (b *Bridge) writeFrame(w io.Writer, frame bridgeFrame) error {
+	data, err := encodeFrame(frame)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readFrame reads a single frame from the connection.
+func (b *Bridge) readFrame(r io.Reader, frame *bridgeFrame) error {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if uint64(n) > uint64(b.maxFrameSize) {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, n)
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, frame)
+}
+
+// Peers returns the UIDs of the connected peers, sorted.
+func (b *Bridge) Peers() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	uids := make([]string, 0, len(b.peers))
+	for uid := range b.peers {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// Close disconnects the Bridge from its peers, stops listening and unsubscribes it
+// from the Streamer. Calling Close more than once has no effect.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		return nil
+	}
+	b.cancel()
+
+	var errs []error
+	for _, listener := range b.listeners {
+		if err := listener.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, peer := range b.peers {
+		peer.close()
+	}
+	for conn := range b.conns {
+		_ = conn.Close()
+	}
+	b.mu.Unlock()
+
+	b.streamer.Unsubscribe(b.uid)
+	b.wg.Wait()
+	return errors.Join(errs...)
+}
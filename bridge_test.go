@@ -0,0 +1,380 @@
+package sirkeji
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBridge(t *testing.T, streamer Streamer, uid string, opts ...BridgeOption) *Bridge {
+	t.Helper()
+
+	bridge, err := NewBridge(streamer, uid, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = bridge.Close() })
+	return bridge
+}
+
+func listenBridge(t *testing.T, bridge *Bridge, network, address string) string {
+	t.Helper()
+
+	addr, err := bridge.Listen(network, address)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	return addr.String()
+}
+
+func waitForPeers(t *testing.T, bridge *Bridge, expected ...string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !slices.Equal(bridge.Peers(), expected) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to be connected to %v, got %v", bridge.uid, expected, bridge.Peers())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func receiveBridged(t *testing.T, ch chan Event) Event {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a bridged event")
+		return Event{}
+	}
+}
+
+func expectNoEvent(t *testing.T, ch chan Event) {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		t.Errorf("expected no event, got %s %q from %s", event.Type, event.Meta, event.Header(BridgePathHeader))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestBridgeForwardsEvents ensures events flow both ways between two streamers, with
+// their payload and without being echoed back.
+func TestBridgeForwardsEvents(t *testing.T) {
+	tests := []struct {
+		network string
+		address string
+	}{
+		{"tcp", "127.0.0.1:0"},
+		{"unix", filepath.Join(t.TempDir(), "bridge.sock")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			east, west := NewStreamer(), NewStreamer()
+			eastCh, _ := east.Subscribe("east-subscriber")
+			westCh, _ := west.Subscribe("west-subscriber")
+
+			eastBridge := newTestBridge(t, east, "east")
+			westBridge := newTestBridge(t, west, "west")
+			if err := westBridge.Connect(tt.network, listenBridge(t, eastBridge, tt.network, tt.address)); err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+			waitForPeers(t, westBridge, "east")
+			waitForPeers(t, eastBridge, "west")
+
+			order := codecTestOrder{ID: "order-1", Items: []string{"tea"}, Total: 1.5}
+			sent := codecTestJSONOrder.New("checkout", "", order).WithHeader("tenant", "acme")
+			west.Publish(sent)
+
+			received := receiveBridged(t, eastCh)
+			if received.ID != sent.ID || received.CorrelationID != sent.ID || received.Header("tenant") != "acme" {
+				t.Errorf("expected %+v, got %+v", sent, received)
+			}
+			if !reflect.DeepEqual(received.Payload, order) {
+				t.Errorf("expected payload %+v, got %+v", order, received.Payload)
+			}
+			if path := received.Header(BridgePathHeader); path != "west" {
+				t.Errorf("expected bridge path west, got %q", path)
+			}
+
+			east.Publish(InfoEvent("east", "reply"))
+
+			if local := receiveBridged(t, westCh); local.ID != sent.ID {
+				t.Errorf("expected the local event first, got %+v", local)
+			}
+			if reply := receiveBridged(t, westCh); reply.Meta != "reply" || reply.Header(BridgePathHeader) != "east" {
+				t.Errorf("expected the reply from east, got %+v", reply)
+			}
+			if local := receiveBridged(t, eastCh); local.Meta != "reply" {
+				t.Errorf("expected the local reply, got %+v", local)
+			}
+			expectNoEvent(t, westCh)
+			expectNoEvent(t, eastCh)
+		})
+	}
+}
+
+// TestBridgeForwardingRules ensures only the forwarded and accepted event types cross the bridge.
+func TestBridgeForwardingRules(t *testing.T) {
+	east, west := NewStreamer(), NewStreamer()
+	eastCh, _ := east.Subscribe("east-subscriber")
+
+	eastBridge := newTestBridge(t, east, "east", WithAcceptTypes("orders.created", Error, Shutdown))
+	westBridge := newTestBridge(t, west, "west", WithForwardTypes("orders.>", Info))
+	_ = westBridge.Connect("tcp", listenBridge(t, eastBridge, "tcp", "127.0.0.1:0"))
+	waitForPeers(t, westBridge, "east")
+
+	west.Publish(NewEvent("checkout", "orders.cancelled", "", nil))
+	west.Publish(ErrorEvent("checkout", "not forwarded"))
+	west.Publish(InfoEvent("checkout", "not accepted"))
+	west.Publish(NewEvent("checkout", "orders.created", "", nil))
+
+	if received := receiveBridged(t, eastCh); received.Type != "orders.created" {
+		t.Errorf("expected orders.created, got %s", received.Type)
+	}
+	expectNoEvent(t, eastCh)
+}
+
+// TestBridgeSkipsShutdown ensures Shutdown events aren't forwarded by default.
+func TestBridgeSkipsShutdown(t *testing.T) {
+	east, west := NewStreamer(), NewStreamer()
+	eastCh, _ := east.Subscribe("east-subscriber")
+
+	eastBridge := newTestBridge(t, east, "east")
+	westBridge := newTestBridge(t, west, "west")
+	_ = westBridge.Connect("tcp", listenBridge(t, eastBridge, "tcp", "127.0.0.1:0"))
+	waitForPeers(t, westBridge, "east")
+
+	west.Publish(NewEvent("main", Shutdown, "", nil))
+	west.Publish(InfoEvent("main", "after shutdown"))
+
+	if received := receiveBridged(t, eastCh); received.Type != Info {
+		t.Errorf("expected the Info event only, got %s", received.Type)
+	}
+}
+
+// TestBridgeSkipsUncodedPayloads ensures events with a payload but no Codec are skipped
+// without being logged, while the others are still forwarded.
+func TestBridgeSkipsUncodedPayloads(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	east, west := NewStreamer(), NewStreamer()
+	eastCh, _ := east.Subscribe("east-subscriber")
+
+	eastBridge := newTestBridge(t, east, "east")
+	westBridge := newTestBridge(t, west, "west")
+	_ = westBridge.Connect("tcp", listenBridge(t, eastBridge, "tcp", "127.0.0.1:0"))
+	waitForPeers(t, westBridge, "east")
+
+	west.Publish(NewEvent("checkout", Info, "uncoded", struct{ Total int }{3}))
+	west.Publish(InfoEvent("checkout", "forwarded"))
+
+	if received := receiveBridged(t, eastCh); received.Meta != "forwarded" {
+		t.Errorf("expected the event without payload only, got %q", received.Meta)
+	}
+	_ = westBridge.Close()
+	if strings.Contains(logged.String(), "didn't forward") {
+		t.Errorf("expected the uncoded event to be skipped silently, got %q", logged.String())
+	}
+}
+
+// TestBridgeCloseWhilePublishing ensures Close returns while events received from a peer
+// wait for a full local queue.
+func TestBridgeCloseWhilePublishing(t *testing.T) {
+	east, west := NewStreamer(), NewStreamer()
+	_, _ = east.Subscribe("stalled-subscriber", WithQueueSize(1))
+
+	eastBridge := newTestBridge(t, east, "east")
+	westBridge := newTestBridge(t, west, "west")
+	_ = westBridge.Connect("tcp", listenBridge(t, eastBridge, "tcp", "127.0.0.1:0"))
+	waitForPeers(t, westBridge, "east")
+
+	for i := 0; i < 3; i++ {
+		west.Publish(InfoEvent("checkout", "stalled"))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		_ = eastBridge.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Close to return")
+	}
+}
+
+// TestBridgeRelay ensures events are relayed across a chain of bridges only with WithRelay.
+func TestBridgeRelay(t *testing.T) {
+	for _, relay := range []bool{false, true} {
+		t.Run(map[bool]string{false: "direct", true: "relay"}[relay], func(t *testing.T) {
+			first, hub, last := NewStreamer(), NewStreamer(), NewStreamer()
+			firstCh, _ := first.Subscribe("first-subscriber")
+			lastCh, _ := last.Subscribe("last-subscriber")
+
+			var hubOpts []BridgeOption
+			if relay {
+				hubOpts = append(hubOpts, WithRelay())
+			}
+			hubBridge := newTestBridge(t, hub, "hub", hubOpts...)
+			addr := listenBridge(t, hubBridge, "tcp", "127.0.0.1:0")
+			firstBridge := newTestBridge(t, first, "first")
+			lastBridge := newTestBridge(t, last, "last")
+			_ = firstBridge.Connect("tcp", addr)
+			_ = lastBridge.Connect("tcp", addr)
+			waitForPeers(t, hubBridge, "first", "last")
+
+			first.Publish(InfoEvent("first", "hello"))
+			receiveBridged(t, firstCh)
+
+			if !relay {
+				expectNoEvent(t, lastCh)
+				return
+			}
+			if path := receiveBridged(t, lastCh).Header(BridgePathHeader); path != "first,hub" {
+				t.Errorf("expected bridge path first,hub, got %q", path)
+			}
+			expectNoEvent(t, firstCh)
+		})
+	}
+}
+
+// TestBridgeReconnect ensures a bridge reconnects once its peer is back.
+func TestBridgeReconnect(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "bridge.sock")
+	west := NewStreamer()
+	westBridge := newTestBridge(t, west, "west", WithReconnectBackoff(FixedRetry(1000, 10*time.Millisecond)))
+
+	eastBridge := newTestBridge(t, NewStreamer(), "east")
+	listenBridge(t, eastBridge, "unix", address)
+	_ = westBridge.Connect("unix", address)
+	waitForPeers(t, westBridge, "east")
+
+	if err := eastBridge.Close(); err != nil {
+		t.Fatalf("failed to close bridge: %v", err)
+	}
+	waitForPeers(t, westBridge)
+
+	east := NewStreamer()
+	eastCh, _ := east.Subscribe("east-subscriber")
+	listenBridge(t, newTestBridge(t, east, "east"), "unix", address)
+	waitForPeers(t, westBridge, "east")
+
+	west.Publish(InfoEvent("west", "back again"))
+	if received := receiveBridged(t, eastCh); received.Meta != "back again" {
+		t.Errorf("expected the event published after reconnecting, got %q", received.Meta)
+	}
+}
+
+// TestBridgeHandshake ensures bridges with the same UID aren't linked.
+func TestBridgeHandshake(t *testing.T) {
+	east := newTestBridge(t, NewStreamer(), "twin")
+	west := newTestBridge(t, NewStreamer(), "twin", WithReconnectBackoff(NoRetry))
+	_ = west.Connect("tcp", listenBridge(t, east, "tcp", "127.0.0.1:0"))
+
+	time.Sleep(50 * time.Millisecond)
+	if len(east.Peers()) != 0 || len(west.Peers()) != 0 {
+		t.Errorf("expected no peers, got %v and %v", east.Peers(), west.Peers())
+	}
+	if err := west.Connect("tcp", "127.0.0.1:0"); err != nil {
+		t.Errorf("expected Connect to succeed before Close, got %v", err)
+	}
+
+	_ = west.Close()
+	if err := west.Connect("tcp", "127.0.0.1:0"); !errors.Is(err, ErrBridgeClosed) {
+		t.Errorf("expected ErrBridgeClosed, got %v", err)
+	}
+	if _, err := west.Listen("tcp", "127.0.0.1:0"); !errors.Is(err, ErrBridgeClosed) {
+		t.Errorf("expected ErrBridgeClosed, got %v", err)
+	}
+}
+
+// TestBridgeSilentPeer ensures peers that never introduce themselves are cut off by the
+// handshake timeout, and can't hold up Close.
+func TestBridgeSilentPeer(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		close   bool
+	}{
+		{"handshake timeout", 20 * time.Millisecond, false},
+		{"close", time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := newTestBridge(t, NewStreamer(), "east", WithHandshakeTimeout(tt.timeout))
+			conn, err := net.Dial("tcp", listenBridge(t, bridge, "tcp", "127.0.0.1:0"))
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer conn.Close()
+
+			var hello bridgeFrame
+			if err := bridge.readFrame(conn, &hello); err != nil || hello.Hello != "east" {
+				t.Fatalf("expected the hello frame, got %+v and %v", hello, err)
+			}
+
+			closed := make(chan struct{})
+			if tt.close {
+				go func() {
+					_ = bridge.Close()
+					close(closed)
+				}()
+			}
+
+			_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+				t.Errorf("expected the connection to be closed, got %v", err)
+			}
+			if !tt.close {
+				return
+			}
+			select {
+			case <-closed:
+			case <-time.After(2 * time.Second):
+				t.Fatal("expected Close to return")
+			}
+		})
+	}
+}
+
+// TestBridgeFrames ensures frames are length-prefixed and bounded in size.
+func TestBridgeFrames(t *testing.T) {
+	bridge := &Bridge{maxFrameSize: 64}
+	var buf bytes.Buffer
+
+	if err := bridge.writeFrame(&buf, bridgeFrame{Hello: "east"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size := buf.Bytes()[:4]; !bytes.Equal(size, []byte{0, 0, 0, byte(buf.Len() - 4)}) {
+		t.Errorf("expected a big-endian length prefix of %d, got %v", buf.Len()-4, size)
+	}
+
+	var frame bridgeFrame
+	if err := bridge.readFrame(&buf, &frame); err != nil || frame.Hello != "east" {
+		t.Errorf("expected the hello frame, got %+v and %v", frame, err)
+	}
+
+	_ = bridge.writeFrame(&buf, bridgeFrame{Hello: string(make([]byte, 100))})
+	if err := bridge.readFrame(&buf, &frame); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("expected ErrFrameTooLarge, got %v", err)
+	}
+}
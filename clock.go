@@ -0,0 +1,127 @@
+package sirkeji
+
+import "time"
+
+// Clock tells the time and creates timers.
+//
+// Components depending on time take a Clock rather than calling the time package
+// directly, so tests can replace SystemClock with a fake clock advanced manually,
+// e.g. sirkejitest.FakeClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// Sleep pauses the current goroutine for at least the duration.
+	Sleep(d time.Duration)
+
+	// NewTimer creates a Timer sending the current time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker creates a Ticker sending the current time on its channel every period.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event timer created by a Clock, see time.Timer.
+type Timer interface {
+	// C returns the channel the time is sent on when the Timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing, it reports whether the Timer was active.
+	Stop() bool
+
+	// Reset changes the Timer to fire after the duration, it reports whether the Timer was active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is a periodic timer created by a Clock, see time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are sent on.
+	C() <-chan time.Time
+
+	// Stop turns off the Ticker.
+	Stop()
+
+	// Reset stops the Ticker and resets its period to the duration.
+	Reset(d time.Duration)
+}
+
+// SystemClock is the Clock of the time package, used by default.
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock with the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTimer implements Timer with a time.Timer.
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// systemTicker implements Ticker with a time.Ticker.
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// ClockedSubscriber is an optional interface for Subscribers depending on time.
+//
+// When a Subscriber implements it, the SubscriptionManager hands it its Clock before
+// subscribing it to the Streamer, so no event is processed without it and the Subscriber
+// can be tested with a fake clock, see WithClock.
+type ClockedSubscriber interface {
+	Subscriber
+
+	// SetClock sets the Clock the Subscriber tells the time with.
+	SetClock(clock Clock)
+}
+
+// WithClock sets the Clock of the SubscriptionManager, SystemClock by default.
+//
+// The Clock times the processing of events, paces retries and timestamps dead letters,
+// and is handed to Subscribers implementing ClockedSubscriber.
+//
+// Example:
+//
+//	clock := sirkejitest.NewFakeClock(time.Now())
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithClock(clock))
+func WithClock(clock Clock) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.clock = clock
+	}
+}
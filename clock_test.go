@@ -0,0 +1,165 @@
+package sirkeji
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSystemClock ensures SystemClock follows the time package.
+func TestSystemClock(t *testing.T) {
+	start := SystemClock.Now()
+	SystemClock.Sleep(5 * time.Millisecond)
+	if elapsed := SystemClock.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("Expected at least 5ms to elapse, got %v", elapsed)
+	}
+
+	timer := SystemClock.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the timer to fire")
+	}
+
+	ticker := SystemClock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticker.C():
+		case <-time.After(time.Second):
+			t.Fatal("Expected the ticker to tick")
+		}
+	}
+}
+
+// frozenClock is a Clock stuck at a fixed time.
+type frozenClock struct {
+	Clock
+	now time.Time
+}
+
+func (c frozenClock) Now() time.Time {
+	return c.now
+}
+
+// clockedSubscriber is a MockSubscriber recording the Clock handed to it.
+type clockedSubscriber struct {
+	*MockSubscriber
+	clock Clock
+	// processedWith holds the Clock set when each event was processed, and whether
+	// Subscribed was called by then.
+	processedWith []Clock
+	subscribed    []bool
+	mu            sync.Mutex
+}
+
+func (s *clockedSubscriber) SetClock(clock Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = clock
+}
+
+func (s *clockedSubscriber) Process(event Event) {
+	s.MockSubscriber.Lock()
+	subscribed := s.MockSubscriber.subscribed
+	s.MockSubscriber.Unlock()
+
+	s.mu.Lock()
+	s.processedWith = append(s.processedWith, s.clock)
+	s.subscribed = append(s.subscribed, subscribed)
+	s.mu.Unlock()
+
+	s.MockSubscriber.Process(event)
+}
+
+// TestWithClock ensures the manager hands its Clock to ClockedSubscribers.
+func TestWithClock(t *testing.T) {
+	streamer := NewStreamer()
+
+	tests := []struct {
+		name     string
+		opts     []ManagerOption
+		expected Clock
+	}{
+		{"default", nil, SystemClock},
+		{"custom", []ManagerOption{WithClock(frozenClock{SystemClock, time.Unix(0, 0)})}, frozenClock{SystemClock, time.Unix(0, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &clockedSubscriber{MockSubscriber: NewMockSubscriber("clocked-" + tt.name)}
+			manager, err := NewSubscriptionManager(streamer, sub, tt.opts...)
+			if err != nil {
+				t.Fatalf("Failed to create subscription manager: %v", err)
+			}
+			if err := manager.Subscribe(); err != nil {
+				t.Fatalf("Failed to subscribe: %v", err)
+			}
+			defer manager.Unsubscribe()
+
+			sub.mu.Lock()
+			defer sub.mu.Unlock()
+			if sub.clock != tt.expected {
+				t.Errorf("Expected the subscriber to get %v, got %v", tt.expected, sub.clock)
+			}
+		})
+	}
+}
+
+// TestClockSetBeforeProcessing ensures replayed events are processed with the manager's
+// Clock, after Subscribed was called.
+func TestClockSetBeforeProcessing(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	streamer := NewStreamer(WithEventLog(eventLog))
+	for i := 0; i < 3; i++ {
+		streamer.Publish(numberedEvent(i))
+	}
+
+	clock := frozenClock{SystemClock, time.Unix(0, 0)}
+	sub := &clockedSubscriber{MockSubscriber: NewMockSubscriber("clocked")}
+	subscribeWith(t, streamer, sub, WithClock(clock), WithSequentialProcessing(), WithSubscribeOptions(FromBeginning()))
+
+	deadline := time.Now().Add(time.Second)
+	for len(sub.GetProcessedEvents()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the replayed events to be processed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	for i, processedWith := range sub.processedWith {
+		if processedWith != clock || !sub.subscribed[i] {
+			t.Errorf("Expected event %d to be processed with %v after Subscribed, got %v and %v", i, clock, processedWith, sub.subscribed[i])
+		}
+	}
+}
+
+// TestDeadLetterUsesClock ensures dead letters are timestamped with the manager's Clock.
+func TestDeadLetterUsesClock(t *testing.T) {
+	streamer := NewStreamer()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler, records := deadLetterRecorder()
+	subscribeWith(t, streamer, NewErrorSubscriber(newFlakyProcessor("clocked-flaky", 10, errTestFailure)),
+		WithClock(frozenClock{SystemClock, now}), WithDeadLetterHandler(handler))
+
+	streamer.Publish(InfoEvent("test", "always fails"))
+
+	var record *DeadLetterRecord
+	select {
+	case record = <-records:
+	case <-time.After(time.Second):
+		t.Fatal("expected the event to be dead-lettered")
+	}
+
+	if !record.FailedAt.Equal(now) || !record.Attempts[0].Time.Equal(now) {
+		t.Errorf("Expected the record to be timestamped %v, got %v and %v", now, record.FailedAt, record.Attempts[0].Time)
+	}
+}
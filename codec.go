@@ -0,0 +1,322 @@
+package sirkeji
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Codec encodes and decodes event payloads.
+//
+// Implementations must be safe for concurrent use.
+type Codec interface {
+	// Name returns a short, unique name of the codec, e.g. "json".
+	Name() string
+
+	// Marshal encodes the value.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSONCodec encodes payloads with encoding/json.
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec encodes payloads with encoding/gob.
+	GobCodec Codec = gobCodec{}
+)
+
+var (
+	// ErrNoPayloadCodec is returned when encoding or decoding the payload of an EventType
+	// registered without a Codec.
+	ErrNoPayloadCodec = errors.New("event type has no payload codec")
+
+	// ErrUnregisteredEventType is returned when an EventType isn't registered.
+	ErrUnregisteredEventType = errors.New("event type is not registered")
+)
+
+// jsonCodec implements Codec using encoding/json.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// gobCodec implements Codec using encoding/gob.
+type gobCodec struct{}
+
+func (gobCodec) Name() string {
+	return "gob"
+}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// WithPayload binds an EventType to the Go type of its payloads and the Codec used to encode them.
+//
+// Parameters:
+//   - prototype: A value of the payload type, e.g. Order{} or &Order{}. Must not be nil.
+//   - codec: The Codec encoding the payloads.
+//
+// Example:
+//
+//	RegisterEventType("OrderPlaced", WithPayload(Order{}, JSONCodec))
+func WithPayload(prototype interface{}, codec Codec) EventTypeOption {
+	if prototype == nil {
+		panic("payload prototype must not be nil")
+	}
+	return func(info *eventTypeInfo) {
+		info.payloadType = reflect.TypeOf(prototype)
+		info.codec = codec
+	}
+}
+
+// WithCodec sets the Codec encoding the payloads of an EventType.
+//
+// Without a payload type bound by WithPayload or DefineEvent, payloads are encoded and
+// decoded as an interface{}, e.g. decoded as map[string]interface{} for JSONCodec. GobCodec
+// then restores their concrete types, which must be registered with gob.Register unless
+// they are basic types.
+//
+// Example:
+//
+//	var OrderPlaced = DefineEvent[Order]("OrderPlaced", WithCodec(GobCodec))
+func WithCodec(codec Codec) EventTypeOption {
+	return func(info *eventTypeInfo) {
+		info.codec = codec
+	}
+}
+
+// withPayloadType binds the payload type while keeping any configured Codec.
+func withPayloadType(payloadType reflect.Type) EventTypeOption {
+	return func(info *eventTypeInfo) {
+		info.payloadType = payloadType
+	}
+}
+
+// HasPayloadCodec reports whether the payloads of the EventType can be encoded.
+func HasPayloadCodec(eventType EventType) bool {
+	info, _ := lookupEventType(eventType)
+	return info.codec != nil
+}
+
+// EncodePayload encodes the payload of the event with the Codec of its EventType.
+//
+// Parameters:
+//   - event: The Event whose Payload is encoded.
+//
+// Returns:
+//   - The encoded payload, nil for a nil payload.
+//   - ErrUnregisteredEventType or ErrNoPayloadCodec if the payload can't be encoded.
+//
+// Example:
+//
+//	data, err := EncodePayload(event)
+func EncodePayload(event Event) ([]byte, error) {
+	info, exists := lookupEventType(event.Type)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredEventType, event.Type)
+	}
+	if info.codec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPayloadCodec, event.Type)
+	}
+	if event.Payload == nil {
+		return nil, nil
+	}
+
+	// Without a payload type, the payload is encoded as the interface{} it is decoded into,
+	// so GobCodec records its concrete type.
+	var v interface{} = event.Payload
+	if info.payloadType == nil {
+		v = &event.Payload
+	}
+	data, err := info.codec.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s payload with %s: %w", event.Type, info.codec.Name(), err)
+	}
+	return data, nil
+}
+
+// DecodePayload decodes a payload encoded by EncodePayload.
+//
+// Parameters:
+//   - eventType: The EventType the payload belongs to.
+//   - data: The encoded payload.
+//
+// Returns:
+//   - The decoded payload, of the type bound to the EventType if any, see WithCodec,
+//     or nil for empty data.
+//   - ErrUnregisteredEventType or ErrNoPayloadCodec if the payload can't be decoded.
+//
+// Example:
+//
+//	payload, err := DecodePayload(event.Type, data)
+func DecodePayload(eventType EventType, data []byte) (interface{}, error) {
+	info, exists := lookupEventType(eventType)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredEventType, eventType)
+	}
+	if info.codec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPayloadCodec, eventType)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if info.payloadType == nil {
+		var payload interface{}
+		if err := info.codec.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("decode %s payload with %s: %w", eventType, info.codec.Name(), err)
+		}
+		return payload, nil
+	}
+
+	payload := reflect.New(info.payloadType)
+	if err := info.codec.Unmarshal(data, payload.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s payload with %s: %w", eventType, info.codec.Name(), err)
+	}
+	return payload.Elem().Interface(), nil
+}
+
+// eventEnvelope is the serializable form of an Event, its payload being encoded with
+// the Codec of its EventType.
+type eventEnvelope struct {
+	ID            string            `json:"id"`
+	Time          time.Time         `json:"time"`
+	Publisher     string            `json:"publisher"`
+	Type          EventType         `json:"type"`
+	Meta          string            `json:"meta,omitempty"`
+	Payload       []byte            `json:"payload,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	CausationID   string            `json:"causation_id,omitempty"`
+}
+
+// newEventEnvelope wraps the event, encoding its payload unless it is nil.
+func newEventEnvelope(event Event) (eventEnvelope, error) {
+	env := eventEnvelope{
+		ID:            event.ID,
+		Time:          event.Time,
+		Publisher:     event.Publisher,
+		Type:          event.Type,
+		Meta:          event.Meta,
+		Headers:       event.Headers,
+		CorrelationID: event.CorrelationID,
+		CausationID:   event.CausationID,
+	}
+	if event.Payload != nil {
+		data, err := EncodePayload(event)
+		if err != nil {
+			return eventEnvelope{}, err
+		}
+		env.Payload = data
+	}
+	return env, nil
+}
+
+// event unwraps the envelope, decoding its payload.
+func (env eventEnvelope) event() (Event, error) {
+	event := Event{
+		ID:            env.ID,
+		Time:          env.Time,
+		Publisher:     env.Publisher,
+		Type:          env.Type,
+		Meta:          env.Meta,
+		Headers:       env.Headers,
+		CorrelationID: env.CorrelationID,
+		CausationID:   env.CausationID,
+	}
+	if len(env.Payload) > 0 {
+		payload, err := DecodePayload(env.Type, env.Payload)
+		if err != nil {
+			return Event{}, err
+		}
+		event.Payload = payload
+	}
+	return event, nil
+}
+
+// eventJSON is the JSON form of an Event served to web clients, its payload being
+// embedded as JSON rather than encoded with the Codec of its EventType.
+type eventJSON struct {
+	ID            string            `json:"id,omitempty"`
+	Time          time.Time         `json:"time"`
+	Offset        uint64            `json:"offset,omitempty"`
+	Publisher     string            `json:"publisher"`
+	Type          EventType         `json:"type"`
+	Meta          string            `json:"meta,omitempty"`
+	Payload       json.RawMessage   `json:"payload,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	CausationID   string            `json:"causation_id,omitempty"`
+}
+
+// newEventJSON returns the JSON form of the event. Payloads encoding/json can't encode
+// are formatted with fmt.
+func newEventJSON(event Event) eventJSON {
+	ej := eventJSON{
+		ID:            event.ID,
+		Time:          event.Time,
+		Offset:        event.Offset,
+		Publisher:     event.Publisher,
+		Type:          event.Type,
+		Meta:          event.Meta,
+		Headers:       event.Headers,
+		CorrelationID: event.CorrelationID,
+		CausationID:   event.CausationID,
+	}
+	if event.Payload != nil {
+		data, err := json.Marshal(event.Payload)
+		if err != nil {
+			data, _ = json.Marshal(fmt.Sprintf("%+v", event.Payload))
+		}
+		ej.Payload = data
+	}
+	return ej
+}
+
+// payload decodes the JSON payload into the payload type bound to the EventType,
+// or into an interface{} if none is bound.
+func (ej eventJSON) payload() (interface{}, error) {
+	if len(ej.Payload) == 0 {
+		return nil, nil
+	}
+
+	info, _ := lookupEventType(ej.Type)
+	if info.payloadType == nil {
+		var payload interface{}
+		if err := json.Unmarshal(ej.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("decode %s payload: %w", ej.Type, err)
+		}
+		return payload, nil
+	}
+
+	payload := reflect.New(info.payloadType)
+	if err := json.Unmarshal(ej.Payload, payload.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", ej.Type, err)
+	}
+	return payload.Elem().Interface(), nil
+}
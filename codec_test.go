@@ -0,0 +1,122 @@
+package sirkeji
+
+import (
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// codecTestOrder is a custom payload type used by the codec tests.
+type codecTestOrder struct {
+	ID    string
+	Items []string
+	Total float64
+}
+
+var (
+	codecTestJSONOrder            = DefineEvent[codecTestOrder]("CodecTestJSONOrder")
+	codecTestGobOrder             = DefineEvent[*codecTestOrder]("CodecTestGobOrder", WithCodec(GobCodec))
+	codecTestUntyped    EventType = "CodecTestUntyped"
+	codecTestGobUntyped EventType = "CodecTestGobUntyped"
+	codecTestPrototyped EventType = "CodecTestPrototyped"
+	codecTestNoCodec    EventType = "CodecTestNoCodec"
+)
+
+func init() {
+	RegisterEventType(codecTestUntyped, WithCodec(JSONCodec))
+	RegisterEventType(codecTestGobUntyped, WithCodec(GobCodec))
+	gob.Register(codecTestOrder{})
+	RegisterEventType(codecTestPrototyped, WithPayload(codecTestOrder{}, GobCodec))
+	RegisterEventType(codecTestNoCodec)
+}
+
+// TestPayloadRoundTrip ensures payloads survive encoding and decoding with each codec.
+func TestPayloadRoundTrip(t *testing.T) {
+	order := codecTestOrder{ID: "order-1", Items: []string{"tea", "simit"}, Total: 4.5}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  interface{}
+	}{
+		{"JSON value", codecTestJSONOrder.New("test", "", order), order},
+		{"Gob pointer", codecTestGobOrder.New("test", "", &order), &order},
+		{"Gob prototype", NewEvent("test", codecTestPrototyped, "", order), order},
+		{"JSON untyped", NewEvent("test", codecTestUntyped, "", map[string]interface{}{"n": 1.5}), map[string]interface{}{"n": 1.5}},
+		{"Gob untyped basic", NewEvent("test", codecTestGobUntyped, "", 42), 42},
+		{"Gob untyped registered", NewEvent("test", codecTestGobUntyped, "", order), order},
+		{"Zero value", codecTestJSONOrder.New("test", "", codecTestOrder{}), codecTestOrder{}},
+		{"Nil payload", NewEvent("test", codecTestUntyped, "", nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := EncodePayload(tt.event)
+			if err != nil {
+				t.Fatalf("unexpected error encoding: %v", err)
+			}
+			payload, err := DecodePayload(tt.event.Type, data)
+			if err != nil {
+				t.Fatalf("unexpected error decoding: %v", err)
+			}
+			if !reflect.DeepEqual(payload, tt.want) {
+				t.Errorf("expected payload %#v, got %#v", tt.want, payload)
+			}
+		})
+	}
+
+	t.Run("Typed payload after round trip", func(t *testing.T) {
+		event := codecTestJSONOrder.New("test", "", order)
+		data, _ := EncodePayload(event)
+		event.Payload, _ = DecodePayload(event.Type, data)
+
+		if _, err := codecTestJSONOrder.Payload(event); err != nil {
+			t.Errorf("expected the decoded payload to match the definition, got %v", err)
+		}
+	})
+}
+
+// TestPayloadCodecErrors ensures payloads without a codec are rejected.
+func TestPayloadCodecErrors(t *testing.T) {
+	if _, err := EncodePayload(NewEvent("test", codecTestNoCodec, "", 1)); !errors.Is(err, ErrNoPayloadCodec) {
+		t.Errorf("expected ErrNoPayloadCodec, got %v", err)
+	}
+	if _, err := DecodePayload("CodecTestUnknown", []byte("1")); !errors.Is(err, ErrUnregisteredEventType) {
+		t.Errorf("expected ErrUnregisteredEventType, got %v", err)
+	}
+	type unregistered struct{ N int }
+	if _, err := EncodePayload(NewEvent("test", codecTestGobUntyped, "", unregistered{1})); err == nil {
+		t.Error("expected untyped gob payloads of unregistered types to be rejected")
+	}
+	if HasPayloadCodec(codecTestNoCodec) {
+		t.Errorf("expected '%s' not to have a payload codec", codecTestNoCodec)
+	}
+}
+
+// TestEventLogPayloadCodec ensures the EventLog encodes payloads with their registered codec.
+func TestEventLogPayloadCodec(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	order := codecTestOrder{ID: "order-2", Items: []string{"coffee"}, Total: 3}
+	offset, err := eventLog.Append(codecTestJSONOrder.New("test", "", order))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event, err := eventLog.Read(offset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := codecTestJSONOrder.Payload(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, order) {
+		t.Errorf("expected payload %+v, got %+v", order, got)
+	}
+}
@@ -0,0 +1,193 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCronExpression is returned when a cron expression can't be parsed.
+var ErrInvalidCronExpression = errors.New("invalid cron expression")
+
+// cronSearchYears bounds the search for the next activation of a CronSchedule,
+// so expressions that never match (e.g. "0 0 30 2 *") don't loop forever.
+const cronSearchYears = 5
+
+// CronSchedule is a parsed cron expression.
+//
+// Expressions have five space separated fields: minute (0-59), hour (0-23),
+// day of month (1-31), month (1-12) and day of week (0-6, Sunday being 0 or 7).
+// Every field accepts "*", single values, ranges ("1-5"), steps ("*/15", "0-30/10")
+// and comma separated lists of those ("0,30").
+//
+// Like in cron, when both the day of month and the day of week are restricted, a
+// time matches if either of them matches.
+type CronSchedule struct {
+	expr   string
+	minute cronField
+	hour   cronField
+	dom    cronField
+	month  cronField
+	dow    cronField
+	// domStar and dowStar record whether the day fields started with "*".
+	domStar bool
+	dowStar bool
+}
+
+// cronField is a bit set of the values matched by a field.
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// ParseCron parses a five-field cron expression.
+//
+// Parameters:
+//   - expr: The cron expression, e.g. "*/15 9-17 * * 1-5".
+//
+// Returns:
+//   - The parsed CronSchedule.
+//   - An error wrapping ErrInvalidCronExpression if the expression is malformed.
+//
+// Example:
+//
+//	schedule, err := ParseCron("0 9 * * 1")
+//	if err != nil {
+//	    log.Fatalf("bad schedule: %v", err)
+//	}
+//	next := schedule.Next(time.Now()) // next Monday, 09:00
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: expected 5 fields, got %d", ErrInvalidCronExpression, expr, len(fields))
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var parsed [5]cronField
+	for i, field := range fields {
+		f, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidCronExpression, expr, err)
+		}
+		parsed[i] = f
+	}
+
+	// Sunday may be written as 7.
+	dow := parsed[4]
+	if dow.has(7) {
+		dow = dow&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		expr:    expr,
+		minute:  parsed[0],
+		hour:    parsed[1],
+		dom:     parsed[2],
+		month:   parsed[3],
+		dow:     dow,
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps.
+func parseCronField(field string, min, max int) (cronField, error) {
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rng)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+// String returns the cron expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next returns the first time matching the schedule strictly after t, in t's location.
+//
+// Returns the zero time if the schedule doesn't match any time in the next years.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+
+wrap:
+	for t.Year() <= limit {
+		for !c.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+		for !c.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+		for !c.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rules for the day of month and day of week fields.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := c.dom.has(t.Day()), c.dow.has(int(t.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
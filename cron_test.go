@@ -0,0 +1,50 @@
+package sirkeji
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCronNext ensures cron schedules activate at the expected times.
+func TestCronNext(t *testing.T) {
+	from := time.Date(2024, time.November, 26, 11, 8, 41, 0, time.UTC) // a Tuesday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.November, 26, 11, 9, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.November, 26, 11, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, time.November, 27, 9, 0, 0, 0, time.UTC)},
+		{"30 8-10,14 * * *", time.Date(2024, time.November, 26, 14, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1", time.Date(2024, time.December, 2, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 1 *", time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 3", time.Date(2024, time.November, 27, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestParseCronErrors ensures malformed expressions are rejected.
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCronExpression) {
+			t.Errorf("expected ErrInvalidCronExpression for %q, got %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned when a dead letter isn't held by the DeadLetterQueue.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// Attempt describes a failed attempt to process an event.
+//
+// Fields:
+//   - Number: The number of the attempt, starting at 1.
+//   - Time: The time the attempt started.
+//   - Duration: How long the attempt took.
+//   - Err: The error returned by the attempt.
+type Attempt struct {
+	Number   int
+	Time     time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// DeadLetterRecord describes an event an ErrorProcessor failed to process.
+//
+// It is published as the Payload of a DeadLetter event by the SubscriptionManager,
+// unless a custom DeadLetterHandler is configured with WithDeadLetterHandler.
+//
+// Fields:
+//   - ID: The unique identifier of the record.
+//   - SubscriberUid: The unique identifier of the failing Subscriber.
+//   - Event: The Event that couldn't be processed.
+//   - Err: The error returned by the last attempt.
+//   - ErrorChain: The messages of Err and of every error it wraps, outermost first.
+//   - Attempts: Every failed attempt, in order.
+//   - FailedAt: The time the event was dead-lettered.
+type DeadLetterRecord struct {
+	ID            string
+	SubscriberUid string
+	Event         Event
+	Err           error
+	ErrorChain    []string
+	Attempts      []Attempt
+	FailedAt      time.Time
+}
+
+// newDeadLetterRecord creates the record of an event that failed every attempt at the given time.
+func newDeadLetterRecord(subscriberUid string, event Event, attempts []Attempt, failedAt time.Time) *DeadLetterRecord {
+	err := attempts[len(attempts)-1].Err
+	return &DeadLetterRecord{
+		ID:            newEventID(),
+		SubscriberUid: subscriberUid,
+		Event:         event,
+		Err:           err,
+		ErrorChain:    errorChain(err),
+		Attempts:      attempts,
+		FailedAt:      failedAt,
+	}
+}
+
+// errorChain returns the messages of err and of every error it wraps, depth first.
+func errorChain(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	chain := []string{err.Error()}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		chain = append(chain, errorChain(wrapped.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, e := range wrapped.Unwrap() {
+			chain = append(chain, errorChain(e)...)
+		}
+	}
+	return chain
+}
+
+// Error implements the error interface.
+func (r *DeadLetterRecord) Error() string {
+	return fmt.Sprintf("subscriber %s failed to process %s event after %d attempt(s): %v",
+		r.SubscriberUid, r.Event.Type, len(r.Attempts), r.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (r *DeadLetterRecord) Unwrap() error {
+	return r.Err
+}
+
+// DeadLetterEvent creates the DeadLetter event describing the failure.
+//
+// Returns:
+//   - An Event with the `DeadLetter` EventType, published by the failing Subscriber,
+//     caused by the failed Event and carrying the DeadLetterRecord as its Payload.
+func (r *DeadLetterRecord) DeadLetterEvent() Event {
+	return r.Event.Derive(r.SubscriberUid, DeadLetter, r.Error(), r)
+}
+
+// DeadLetterHandler is called with every event an ErrorProcessor failed to process.
+type DeadLetterHandler func(record *DeadLetterRecord)
+
+// WithDeadLetterHandler replaces the default dead letter handling of the SubscriptionManager.
+//
+// By default dead letters are published back onto the Streamer as DeadLetter events
+// created by DeadLetterRecord.DeadLetterEvent, to be collected by a DeadLetterQueue.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, NewErrorSubscriber(mailer),
+//	    WithDeadLetterHandler(func(record *DeadLetterRecord) {
+//	        log.Printf("%v, chain: %v", record, record.ErrorChain)
+//	    }))
+func WithDeadLetterHandler(handler DeadLetterHandler) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.onDeadLetter = handler
+	}
+}
+
+// handleDeadLetter passes the record to the configured DeadLetterHandler or publishes it
+// as a DeadLetter event.
+func (sm *SubscriptionManager) handleDeadLetter(record *DeadLetterRecord) {
+	if sm.onDeadLetter != nil {
+		sm.onDeadLetter(record)
+		return
+	}
+
+	// A subscriber failing on its own dead letter would loop forever.
+	if cause, ok := record.Event.Payload.(*DeadLetterRecord); ok && cause.SubscriberUid == record.SubscriberUid {
+		log.Printf("[%s] failed again while processing its own dead letter: %v\n", record.SubscriberUid, record.Err)
+		return
+	}
+
+	sm.streamer.Publish(record.DeadLetterEvent())
+}
+
+// DeadLetterQueue is a Subscriber storing the DeadLetter events published on the Streamer,
+// so the failed events can be inspected and re-published later.
+type DeadLetterQueue struct {
+	uid     string
+	publish func(e Event)
+
+	// records holds the dead letters by ID.
+	records map[string]*DeadLetterRecord
+	mu      sync.RWMutex
+}
+
+// NewDeadLetterQueue creates an empty DeadLetterQueue.
+//
+// Parameters:
+//   - uid: The unique identifier of the queue.
+//   - publish: The function used to re-publish failed events, e.g. streamer.Publish.
+//
+// Example:
+//
+//	dlq := sirkeji.NewDeadLetterQueue("dead-letters", streamer.Publish)
+//	sirkeji.Subscribe(streamer, dlq)
+func NewDeadLetterQueue(uid string, publish func(e Event)) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		uid:     uid,
+		publish: publish,
+		records: make(map[string]*DeadLetterRecord),
+	}
+}
+
+// Uid returns the unique identifier of the DeadLetterQueue.
+func (q *DeadLetterQueue) Uid() string {
+	return q.uid
+}
+
+// EventTypes subscribes the DeadLetterQueue to DeadLetter events only.
+func (q *DeadLetterQueue) EventTypes() []EventType {
+	return []EventType{DeadLetter}
+}
+
+// Process stores the DeadLetterRecord carried by the event.
+func (q *DeadLetterQueue) Process(event Event) {
+	record, ok := event.Payload.(*DeadLetterRecord)
+	if !ok {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.records[record.ID] = record
+}
+
+// Subscribed is a no-op.
+func (q *DeadLetterQueue) Subscribed() {}
+
+// Unsubscribed is a no-op.
+func (q *DeadLetterQueue) Unsubscribed() {}
+
+// Records returns the stored dead letters, oldest first.
+func (q *DeadLetterQueue) Records() []*DeadLetterRecord {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	records := make([]*DeadLetterRecord, 0, len(q.records))
+	for _, record := range q.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].FailedAt.Before(records[j].FailedAt)
+	})
+	return records
+}
+
+// Len returns the number of stored dead letters.
+func (q *DeadLetterQueue) Len() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return len(q.records)
+}
+
+// Remove discards a dead letter without re-publishing it.
+//
+// Returns:
+//   - true if the dead letter was stored, false otherwise.
+func (q *DeadLetterQueue) Remove(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, exists := q.records[id]
+	delete(q.records, id)
+	return exists
+}
+
+// Republish publishes the failed event of a dead letter again and removes it from the queue.
+//
+// The event is re-published with a new ID and timestamp, keeping its Publisher, Payload,
+// Headers and CorrelationID, so every Subscriber receives it again.
+//
+// Returns:
+//   - ErrDeadLetterNotFound if the queue doesn't hold the dead letter.
+//
+// Example:
+//
+//	for _, record := range dlq.Records() {
+//	    if errors.Is(record, ErrMailServerDown) {
+//	        _ = dlq.Republish(record.ID)
+//	    }
+//	}
+func (q *DeadLetterQueue) Republish(id string) error {
+	q.mu.Lock()
+	record, exists := q.records[id]
+	delete(q.records, id)
+	q.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	event := record.Event
+	event.ID, event.Time, event.Offset = "", time.Time{}, 0
+	q.publish(event)
+	return nil
+}
+
+// RepublishAll re-publishes every stored dead letter, oldest first, see Republish.
+//
+// Returns:
+//   - The number of re-published events.
+func (q *DeadLetterQueue) RepublishAll() int {
+	count := 0
+	for _, record := range q.Records() {
+		if q.Republish(record.ID) == nil {
+			count++
+		}
+	}
+	return count
+}
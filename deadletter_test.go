@@ -0,0 +1,65 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDeadLetterQueue ensures dead letters are collected and can be re-published.
+func TestDeadLetterQueue(t *testing.T) {
+	streamer := NewStreamer()
+	dlq := NewDeadLetterQueue("dead-letters", streamer.Publish)
+	subscribeWith(t, streamer, dlq)
+
+	processor := newFlakyProcessor("flaky", 1, errTestFailure)
+	subscribeWith(t, streamer, NewErrorSubscriber(processor))
+
+	event := InfoEvent("system", "hello")
+	streamer.Publish(event)
+
+	deadline := time.Now().Add(time.Second)
+	for dlq.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	records := dlq.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(records))
+	}
+	if records[0].Event.ID != event.ID {
+		t.Errorf("expected the dead letter of the published event, got %+v", records[0].Event)
+	}
+
+	if err := dlq.Republish(records[0].ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dlq.Len() != 0 {
+		t.Errorf("expected the dead letter to be removed, got %d", dlq.Len())
+	}
+	if err := dlq.Republish(records[0].ID); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if dlq.Len() != 0 {
+		t.Errorf("expected the re-published event to be processed, got %d dead letters", dlq.Len())
+	}
+}
+
+// TestErrorChain ensures the error chain lists every wrapped error.
+func TestErrorChain(t *testing.T) {
+	inner := errors.New("connection refused")
+	err := fmt.Errorf("send mail: %w", errors.Join(inner, errTestFailure))
+
+	expected := []string{
+		"send mail: connection refused\ntest failure",
+		"connection refused\ntest failure",
+		"connection refused",
+		"test failure",
+	}
+	if chain := errorChain(err); !reflect.DeepEqual(chain, expected) {
+		t.Errorf("expected chain %q, got %q", expected, chain)
+	}
+}
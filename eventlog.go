@@ -0,0 +1,825 @@
+package sirkeji
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSegmentSize is the size in bytes after which the EventLog starts a new segment.
+	DefaultSegmentSize int64 = 64 << 20
+
+	// logSuffix and indexSuffix are the file extensions of segment data and index files.
+	logSuffix   = ".log"
+	indexSuffix = ".idx"
+
+	// recordHeaderSize is the size of the length and checksum preceding every record.
+	recordHeaderSize = 8
+	// indexEntrySize is the size of a single index entry, the record's position in the segment.
+	indexEntrySize = 8
+)
+
+var (
+	// ErrOffsetOutOfRange is returned when reading an offset that isn't in the EventLog.
+	ErrOffsetOutOfRange = errors.New("offset out of range")
+
+	// ErrEventLogClosed is returned when using an EventLog after Close.
+	ErrEventLogClosed = errors.New("event log is closed")
+
+	// ErrCorruptRecord is returned when a record fails its checksum or can't be decoded.
+	ErrCorruptRecord = errors.New("corrupt event log record")
+
+	// ErrNoEventLog is returned when subscribing with FromOffset or FromBeginning to a
+	// Streamer without an EventLog.
+	ErrNoEventLog = errors.New("streamer has no event log")
+)
+
+// EventLog is a file-backed, append-only log of Events.
+//
+// Every appended Event is assigned a monotonically increasing offset, starting at 1.
+// The log is split into segment files named after the offset of their first record,
+// each accompanied by an index file mapping offsets to positions in the segment.
+//
+// Payloads of EventTypes registered with a Codec, see WithPayload and DefineEvent, are
+// encoded with that Codec. Other payloads are encoded with encoding/gob as interface
+// values, so their custom types must be registered with gob.Register before they can be logged.
+// The PanicError, DeadLetterRecord and PayloadTypeError payloads of the library's own Error
+// and DeadLetter events are registered already; their errors and panic values are logged as
+// messages, and the payload of the Event they describe is left out if it can't be encoded.
+// Events failing to encode are logged and still delivered, PublishContext also returns the error.
+//
+// Attach an EventLog to a DefaultStreamer with WithEventLog to record every published
+// Event, and subscribe with FromOffset or FromBeginning to replay the history.
+type EventLog struct {
+	dir         string
+	segmentSize int64
+	syncEach    bool
+
+	segments []*segment
+	next     uint64
+	closed   bool
+	sync.RWMutex
+}
+
+// segment is a single data file of the EventLog and its in-memory index.
+type segment struct {
+	base      uint64
+	data      *os.File
+	index     *os.File
+	size      int64
+	positions []int64
+}
+
+// EventLogOption configures an EventLog opened by OpenEventLog.
+type EventLogOption func(*EventLog)
+
+// WithSegmentSize sets the size in bytes after which a new segment file is started.
+//
+// Example:
+//
+//	log, err := OpenEventLog("data/events", WithSegmentSize(8<<20))
+func WithSegmentSize(size int64) EventLogOption {
+	return func(l *EventLog) {
+		l.segmentSize = size
+	}
+}
+
+// WithSyncOnAppend makes the EventLog flush every appended record and its index entry
+// to stable storage.
+//
+// This trades throughput for durability in case of a power loss, process crashes
+// are survived without it. A DefaultStreamer appends while holding its read lock, so
+// slow syncs also hold up Subscribe and Unsubscribe, see WithEventLog.
+func WithSyncOnAppend() EventLogOption {
+	return func(l *EventLog) {
+		l.syncEach = true
+	}
+}
+
+// FromOffset makes the subscription replay the logged events starting at the given
+// offset before receiving live events. The Streamer must have an EventLog attached.
+//
+// Replayed events are queued regardless of the overflow policy, while live events
+// published during the replay are held back and queued right after it. Up to the queue
+// size of live events are held, more are handled according to the overflow policy.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("projector", FromOffset(lastProcessed+1))
+func FromOffset(offset uint64) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.replayFrom = max(offset, 1)
+	}
+}
+
+// FromBeginning makes the subscription replay every logged event before receiving
+// live events. See FromOffset.
+//
+// Example:
+//
+//	sirkeji.Subscribe(streamer, counter, sirkeji.WithSubscribeOptions(sirkeji.FromBeginning()))
+func FromBeginning() SubscribeOption {
+	return FromOffset(1)
+}
+
+// logRecord is the persisted form of an Event.
+type logRecord struct {
+	Offset        uint64
+	ID            string
+	Time          time.Time
+	Publisher     string
+	Type          EventType
+	Meta          string
+	Payload       interface{}
+	Headers       map[string]string
+	CorrelationID string
+	CausationID   string
+
+	// EncodedPayload holds the payload encoded by the Codec of its EventType, Payload
+	// is left empty in that case.
+	EncodedPayload []byte
+	PayloadEncoded bool
+}
+
+// OpenEventLog opens the EventLog stored in the given directory, creating it if necessary.
+//
+// Parameters:
+//   - dir: The directory holding the segment files.
+//   - opts: Optional EventLogOptions.
+//
+// Returns:
+//   - A pointer to the opened EventLog.
+//   - An error if the directory or its segments can't be opened.
+//
+// Behavior:
+//   - Records partially written by a crashed process are truncated from the last segment.
+//
+// Example:
+//
+//	log, err := OpenEventLog("data/events")
+//	if err != nil {
+//	    log.Fatalf("failed to open event log: %v", err)
+//	}
+//	defer log.Close()
+func OpenEventLog(dir string, opts ...EventLogOption) (*EventLog, error) {
+	l := &EventLog{
+		dir:         dir,
+		segmentSize: DefaultSegmentSize,
+		next:        1,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create event log directory: %w", err)
+	}
+
+	bases, err := l.segmentBases()
+	if err != nil {
+		return nil, err
+	}
+	for i, base := range bases {
+		seg, err := openSegment(dir, base, i == len(bases)-1)
+		if err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+		l.segments = append(l.segments, seg)
+		l.next = base + uint64(len(seg.positions))
+	}
+
+	if len(l.segments) == 0 {
+		if err := l.roll(); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// segmentBases lists the base offsets of the segments found in the log directory.
+func (l *EventLog) segmentBases() ([]uint64, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read event log directory: %w", err)
+	}
+
+	var bases []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, logSuffix) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, logSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, base)
+	}
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+	return bases, nil
+}
+
+// segmentPath returns the path of a segment file with the given base offset and suffix.
+func segmentPath(dir string, base uint64, suffix string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", base, suffix))
+}
+
+// openSegment opens a segment and loads its index.
+//
+// The index of the last segment is rebuilt from its data file, dropping any
+// partially written record at its tail. The index of an earlier segment is rebuilt
+// too when it doesn't cover its data file, e.g. after a power loss.
+func openSegment(dir string, base uint64, last bool) (*segment, error) {
+	data, err := os.OpenFile(segmentPath(dir, base, logSuffix), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open segment: %w", err)
+	}
+	index, err := os.OpenFile(segmentPath(dir, base, indexSuffix), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		_ = data.Close()
+		return nil, fmt.Errorf("open segment index: %w", err)
+	}
+
+	seg := &segment{base: base, data: data, index: index}
+	if last {
+		err = seg.recover()
+	} else {
+		err = seg.loadIndex()
+	}
+	if err != nil {
+		seg.close()
+		return nil, err
+	}
+	return seg, nil
+}
+
+// loadIndex reads the record positions from the index file.
+func (seg *segment) loadIndex() error {
+	raw, err := os.ReadFile(seg.index.Name())
+	if err != nil {
+		return fmt.Errorf("read segment index: %w", err)
+	}
+	for i := 0; i+indexEntrySize <= len(raw); i += indexEntrySize {
+		seg.positions = append(seg.positions, int64(binary.BigEndian.Uint64(raw[i:])))
+	}
+
+	info, err := seg.data.Stat()
+	if err != nil {
+		return fmt.Errorf("stat segment: %w", err)
+	}
+	seg.size = info.Size()
+
+	if !seg.indexed() {
+		log.Printf("[event-log] rebuilding the index of segment %d\n", seg.base)
+		seg.positions = nil
+		return seg.recover()
+	}
+	return nil
+}
+
+// indexed reports whether the loaded index ends with the last record of the data file.
+func (seg *segment) indexed() bool {
+	if len(seg.positions) == 0 {
+		return seg.size == 0
+	}
+	var header [recordHeaderSize]byte
+	last := seg.positions[len(seg.positions)-1]
+	if _, err := seg.data.ReadAt(header[:], last); err != nil {
+		return false
+	}
+	return last+recordHeaderSize+int64(binary.BigEndian.Uint32(header[:4])) == seg.size
+}
+
+// recover scans the data file, rebuilding the index and truncating a torn tail.
+func (seg *segment) recover() error {
+	info, err := seg.data.Stat()
+	if err != nil {
+		return fmt.Errorf("stat segment: %w", err)
+	}
+
+	var (
+		position int64
+		header   [recordHeaderSize]byte
+	)
+	for {
+		if _, err := seg.data.ReadAt(header[:], position); err != nil {
+			break
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		if position+recordHeaderSize+length > info.Size() {
+			break
+		}
+		body := make([]byte, length)
+		if _, err := seg.data.ReadAt(body, position+recordHeaderSize); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:]) {
+			break
+		}
+		seg.positions = append(seg.positions, position)
+		position += recordHeaderSize + length
+	}
+
+	if err := seg.data.Truncate(position); err != nil {
+		return fmt.Errorf("truncate segment: %w", err)
+	}
+	seg.size = position
+
+	index := make([]byte, len(seg.positions)*indexEntrySize)
+	for i, p := range seg.positions {
+		binary.BigEndian.PutUint64(index[i*indexEntrySize:], uint64(p))
+	}
+	if err := seg.index.Truncate(0); err != nil {
+		return fmt.Errorf("truncate segment index: %w", err)
+	}
+	if _, err := seg.index.WriteAt(index, 0); err != nil {
+		return fmt.Errorf("rewrite segment index: %w", err)
+	}
+	return nil
+}
+
+// read decodes the record at the given position of the segment.
+func (seg *segment) read(position int64) (logRecord, error) {
+	var header [recordHeaderSize]byte
+	if _, err := seg.data.ReadAt(header[:], position); err != nil {
+		return logRecord{}, fmt.Errorf("read record header: %w", err)
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header[:4]))
+	if _, err := seg.data.ReadAt(body, position+recordHeaderSize); err != nil {
+		return logRecord{}, fmt.Errorf("read record: %w", err)
+	}
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:]) {
+		return logRecord{}, ErrCorruptRecord
+	}
+
+	var record logRecord
+	if err := gob.NewDecoder(bytes.NewReader(body)).Decode(&record); err != nil {
+		return logRecord{}, fmt.Errorf("%w: %v", ErrCorruptRecord, err)
+	}
+	return record, nil
+}
+
+// sync flushes the segment's data and index files to stable storage.
+func (seg *segment) sync() error {
+	if err := seg.data.Sync(); err != nil {
+		return fmt.Errorf("sync segment: %w", err)
+	}
+	if err := seg.index.Sync(); err != nil {
+		return fmt.Errorf("sync segment index: %w", err)
+	}
+	return nil
+}
+
+// close closes the segment's files.
+func (seg *segment) close() {
+	_ = seg.data.Close()
+	_ = seg.index.Close()
+}
+
+// roll starts a new segment at the next offset. The caller must hold the lock.
+//
+// The segment being completed is flushed to stable storage first, as it's never
+// recovered from its data file again.
+func (l *EventLog) roll() error {
+	if len(l.segments) > 0 {
+		if err := l.segments[len(l.segments)-1].sync(); err != nil {
+			return err
+		}
+	}
+	seg, err := openSegment(l.dir, l.next, true)
+	if err != nil {
+		return err
+	}
+	l.segments = append(l.segments, seg)
+	return nil
+}
+
+// Append writes the event to the end of the log.
+//
+// Parameters:
+//   - event: The Event to append.
+//
+// Returns:
+//   - The offset assigned to the event.
+//   - An error if the event can't be encoded or written.
+//
+// Example:
+//
+//	offset, err := log.Append(InfoEvent("system", "Application started"))
+func (l *EventLog) Append(event Event) (uint64, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return 0, ErrEventLogClosed
+	}
+
+	offset := l.next
+	record, err := newLogRecord(offset, event)
+	if err != nil {
+		return 0, err
+	}
+
+	var body bytes.Buffer
+	if err := gob.NewEncoder(&body).Encode(record); err != nil {
+		return 0, fmt.Errorf("encode %s event: %w", event.Type, err)
+	}
+
+	active := l.segments[len(l.segments)-1]
+	if len(active.positions) > 0 && active.size+recordHeaderSize+int64(body.Len()) > l.segmentSize {
+		if err := l.roll(); err != nil {
+			return 0, err
+		}
+		active = l.segments[len(l.segments)-1]
+	}
+
+	framed := make([]byte, recordHeaderSize+body.Len())
+	binary.BigEndian.PutUint32(framed[:4], uint32(body.Len()))
+	binary.BigEndian.PutUint32(framed[4:8], crc32.ChecksumIEEE(body.Bytes()))
+	copy(framed[recordHeaderSize:], body.Bytes())
+
+	if _, err := active.data.WriteAt(framed, active.size); err != nil {
+		return 0, fmt.Errorf("write record: %w", err)
+	}
+	var entry [indexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], uint64(active.size))
+	if _, err := active.index.WriteAt(entry[:], int64(len(active.positions))*indexEntrySize); err != nil {
+		return 0, fmt.Errorf("write index entry: %w", err)
+	}
+	if l.syncEach {
+		if err := active.sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	active.positions = append(active.positions, active.size)
+	active.size += int64(len(framed))
+	l.next++
+	return offset, nil
+}
+
+// NextOffset returns the offset the next appended event will get.
+func (l *EventLog) NextOffset() uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.next
+}
+
+// Read returns the event stored at the given offset.
+//
+// Returns:
+//   - The Event, with its Offset set.
+//   - ErrOffsetOutOfRange if the offset isn't in the log.
+func (l *EventLog) Read(offset uint64) (Event, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.closed {
+		return Event{}, ErrEventLogClosed
+	}
+
+	seg, position, ok := l.locate(offset)
+	if !ok {
+		return Event{}, fmt.Errorf("%w: %d", ErrOffsetOutOfRange, offset)
+	}
+	record, err := seg.read(position)
+	if err != nil {
+		return Event{}, err
+	}
+	return record.event()
+}
+
+// locate finds the segment and position of an offset. The caller must hold the lock.
+func (l *EventLog) locate(offset uint64) (*segment, int64, bool) {
+	i := sort.Search(len(l.segments), func(i int) bool { return l.segments[i].base > offset }) - 1
+	if i < 0 {
+		return nil, 0, false
+	}
+	seg := l.segments[i]
+	if offset-seg.base >= uint64(len(seg.positions)) {
+		return nil, 0, false
+	}
+	return seg, seg.positions[offset-seg.base], true
+}
+
+// Replay calls fn with every event from the given offset up to the end of the log
+// at the time of the call.
+//
+// Parameters:
+//   - from: The first offset to replay. Offsets before the start of the log are skipped.
+//   - fn: Called with every event in order, returning an error stops the replay.
+//
+// Returns:
+//   - The error returned by fn, or an error reading the log.
+//
+// Example:
+//
+//	err := log.Replay(1, func(event Event) error {
+//	    fmt.Println(event.Offset, event.Type)
+//	    return nil
+//	})
+func (l *EventLog) Replay(from uint64, fn func(Event) error) error {
+	return l.replay(from, l.NextOffset(), fn)
+}
+
+// replay calls fn with the events in the offset range [from, to).
+func (l *EventLog) replay(from, to uint64, fn func(Event) error) error {
+	if from < 1 {
+		from = 1
+	}
+	for offset := from; offset < to; offset++ {
+		event, err := l.Read(offset)
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync flushes the active segment to stable storage.
+func (l *EventLog) Sync() error {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.closed {
+		return ErrEventLogClosed
+	}
+	active := l.segments[len(l.segments)-1]
+	if err := active.index.Sync(); err != nil {
+		return err
+	}
+	return active.data.Sync()
+}
+
+// Close closes all segment files. The EventLog can't be used afterwards.
+func (l *EventLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	var err error
+	if len(l.segments) > 0 {
+		err = l.segments[len(l.segments)-1].data.Sync()
+	}
+	for _, seg := range l.segments {
+		seg.close()
+	}
+	return err
+}
+
+// newLogRecord converts the event into its persisted form.
+func newLogRecord(offset uint64, event Event) (logRecord, error) {
+	record := logRecord{
+		Offset:        offset,
+		ID:            event.ID,
+		Time:          event.Time,
+		Publisher:     event.Publisher,
+		Type:          event.Type,
+		Meta:          event.Meta,
+		Payload:       event.Payload,
+		Headers:       event.Headers,
+		CorrelationID: event.CorrelationID,
+		CausationID:   event.CausationID,
+	}
+
+	if HasPayloadCodec(event.Type) {
+		data, err := EncodePayload(event)
+		if err != nil {
+			return logRecord{}, err
+		}
+		record.Payload = nil
+		record.EncodedPayload = data
+		record.PayloadEncoded = true
+	}
+	return record, nil
+}
+
+// event converts the record back into an Event.
+func (r logRecord) event() (Event, error) {
+	payload := r.Payload
+	if r.PayloadEncoded {
+		var err error
+		if payload, err = DecodePayload(r.Type, r.EncodedPayload); err != nil {
+			return Event{}, err
+		}
+	}
+
+	return Event{
+		ID:            r.ID,
+		Time:          r.Time,
+		Offset:        r.Offset,
+		Publisher:     r.Publisher,
+		Type:          r.Type,
+		Meta:          r.Meta,
+		Payload:       payload,
+		Headers:       r.Headers,
+		CorrelationID: r.CorrelationID,
+		CausationID:   r.CausationID,
+	}, nil
+}
+
+func init() {
+	// The payloads of the Error and DeadLetter events published by the library itself.
+	gob.Register(&PanicError{})
+	gob.Register(&DeadLetterRecord{})
+	gob.Register(&PayloadTypeError{})
+}
+
+// loggedError is an error read back from the EventLog, only its message is persisted.
+type loggedError string
+
+// Error implements the error interface.
+func (e loggedError) Error() string {
+	return string(e)
+}
+
+// errorMessage returns the message of err, empty if err is nil.
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// messageError returns the error read back from the message, nil if it is empty.
+func messageError(message string) error {
+	if message == "" {
+		return nil
+	}
+	return loggedError(message)
+}
+
+// gobEncode encodes the persisted form of one of the library's payloads. If the payload
+// of the Event it describes can't be encoded, it is encoded again without that payload.
+func gobEncode(v interface{}, event *logRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err == nil {
+		return buf.Bytes(), nil
+	}
+
+	event.Payload = nil
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// panicErrorRecord is the persisted form of a PanicError.
+type panicErrorRecord struct {
+	SubscriberUid string
+	Value         string
+	Stack         []byte
+	Event         logRecord
+}
+
+// GobEncode implements gob.GobEncoder so PanicErrors can be logged by an EventLog.
+// The panic value is persisted as its fmt representation, see gobEncode for the Event.
+func (p *PanicError) GobEncode() ([]byte, error) {
+	event, err := newLogRecord(p.Event.Offset, p.Event)
+	if err != nil {
+		return nil, err
+	}
+	record := panicErrorRecord{
+		SubscriberUid: p.SubscriberUid,
+		Value:         fmt.Sprint(p.Value),
+		Stack:         p.Stack,
+		Event:         event,
+	}
+	return gobEncode(&record, &record.Event)
+}
+
+// GobDecode implements gob.GobDecoder, the panic value is read back as a string.
+func (p *PanicError) GobDecode(data []byte) error {
+	var record panicErrorRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+		return err
+	}
+	event, err := record.Event.event()
+	if err != nil {
+		return err
+	}
+
+	*p = PanicError{SubscriberUid: record.SubscriberUid, Value: record.Value, Stack: record.Stack, Event: event}
+	return nil
+}
+
+// deadLetterRecordRecord is the persisted form of a DeadLetterRecord.
+type deadLetterRecordRecord struct {
+	ID            string
+	SubscriberUid string
+	Event         logRecord
+	Err           string
+	ErrorChain    []string
+	Attempts      []attemptRecord
+	FailedAt      time.Time
+}
+
+// attemptRecord is the persisted form of an Attempt.
+type attemptRecord struct {
+	Number   int
+	Time     time.Time
+	Duration time.Duration
+	Err      string
+}
+
+// GobEncode implements gob.GobEncoder so DeadLetterRecords can be logged by an EventLog.
+// Errors are persisted as their messages.
+func (r *DeadLetterRecord) GobEncode() ([]byte, error) {
+	event, err := newLogRecord(r.Event.Offset, r.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	record := deadLetterRecordRecord{
+		ID:            r.ID,
+		SubscriberUid: r.SubscriberUid,
+		Event:         event,
+		Err:           errorMessage(r.Err),
+		ErrorChain:    r.ErrorChain,
+		FailedAt:      r.FailedAt,
+	}
+	for _, a := range r.Attempts {
+		record.Attempts = append(record.Attempts, attemptRecord{Number: a.Number, Time: a.Time, Duration: a.Duration, Err: errorMessage(a.Err)})
+	}
+	return gobEncode(&record, &record.Event)
+}
+
+// GobDecode implements gob.GobDecoder, errors are read back with their messages only.
+func (r *DeadLetterRecord) GobDecode(data []byte) error {
+	var record deadLetterRecordRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+		return err
+	}
+	event, err := record.Event.event()
+	if err != nil {
+		return err
+	}
+
+	*r = DeadLetterRecord{
+		ID:            record.ID,
+		SubscriberUid: record.SubscriberUid,
+		Event:         event,
+		Err:           messageError(record.Err),
+		ErrorChain:    record.ErrorChain,
+		FailedAt:      record.FailedAt,
+	}
+	for _, a := range record.Attempts {
+		r.Attempts = append(r.Attempts, Attempt{Number: a.Number, Time: a.Time, Duration: a.Duration, Err: messageError(a.Err)})
+	}
+	return nil
+}
+
+// payloadTypeErrorRecord is the persisted form of a PayloadTypeError.
+type payloadTypeErrorRecord struct {
+	EventType EventType
+	Event     logRecord
+	Expected  string
+	Actual    string
+}
+
+// GobEncode implements gob.GobEncoder so PayloadTypeErrors can be logged by an EventLog.
+func (e *PayloadTypeError) GobEncode() ([]byte, error) {
+	event, err := newLogRecord(e.Event.Offset, e.Event)
+	if err != nil {
+		return nil, err
+	}
+	record := payloadTypeErrorRecord{EventType: e.EventType, Event: event, Expected: e.Expected, Actual: e.Actual}
+	return gobEncode(&record, &record.Event)
+}
+
+// GobDecode implements gob.GobDecoder.
+func (e *PayloadTypeError) GobDecode(data []byte) error {
+	var record payloadTypeErrorRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+		return err
+	}
+	event, err := record.Event.event()
+	if err != nil {
+		return err
+	}
+
+	*e = PayloadTypeError{EventType: record.EventType, Event: event, Expected: record.Expected, Actual: record.Actual}
+	return nil
+}
@@ -0,0 +1,266 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEventLogAppendAndRead ensures events are assigned increasing offsets and read back intact.
+func TestEventLogAppendAndRead(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	event := NewEvent("test-publisher", Info, "metadata", 42).WithHeader("trace", "abc")
+	for i := uint64(1); i <= 3; i++ {
+		offset, err := eventLog.Append(event)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if offset != i {
+			t.Fatalf("expected offset %d, got %d", i, offset)
+		}
+	}
+
+	read, err := eventLog.Read(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if read.Offset != 2 || read.ID != event.ID || read.Payload != 42 || read.Header("trace") != "abc" {
+		t.Errorf("expected the logged event at offset 2, got %+v", read)
+	}
+	if !read.Time.Equal(event.Time) {
+		t.Errorf("expected Time %v, got %v", event.Time, read.Time)
+	}
+
+	if _, err := eventLog.Read(4); !errors.Is(err, ErrOffsetOutOfRange) {
+		t.Errorf("expected ErrOffsetOutOfRange, got %v", err)
+	}
+}
+
+// TestEventLogLibraryPayloads ensures the payloads of the library's Error and DeadLetter events are logged.
+func TestEventLogLibraryPayloads(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	type unregistered struct{ Secret chan int }
+	failed := stampEvent(NewEvent("test-publisher", "orders.created", "", 42))
+	unencodable := stampEvent(NewEvent("test-publisher", "orders.created", "", unregistered{}))
+	attemptErr := fmt.Errorf("attempt 1: %w", errors.New("boom"))
+
+	events := []Event{
+		(&PanicError{SubscriberUid: "worker", Value: errors.New("nil map"), Stack: []byte("stack"), Event: failed}).ErrorEvent(),
+		newDeadLetterRecord("worker", failed, []Attempt{{Number: 1, Time: failed.Time, Duration: time.Second, Err: attemptErr}}, failed.Time).DeadLetterEvent(),
+		unencodable.Derive("worker", Error, "", &PayloadTypeError{EventType: "orders.created", Event: unencodable, Expected: "int", Actual: "unregistered"}),
+	}
+	for _, event := range events {
+		if _, err := eventLog.Append(event); err != nil {
+			t.Fatalf("expected the %T payload to be logged, got %v", event.Payload, err)
+		}
+	}
+
+	read, err := eventLog.Read(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p, ok := read.Payload.(*PanicError); !ok || p.Value != "nil map" || p.Event.ID != failed.ID || p.Event.Payload != 42 {
+		t.Errorf("expected the PanicError, got %#v", read.Payload)
+	}
+
+	read, err = eventLog.Read(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record, ok := read.Payload.(*DeadLetterRecord)
+	if !ok || record.Err.Error() != attemptErr.Error() || len(record.Attempts) != 1 || record.Attempts[0].Duration != time.Second {
+		t.Fatalf("expected the DeadLetterRecord, got %#v", read.Payload)
+	}
+	if len(record.ErrorChain) != 2 || !record.FailedAt.Equal(failed.Time) {
+		t.Errorf("expected the error chain and failure time, got %+v", record)
+	}
+
+	read, err = eventLog.Read(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, ok := read.Payload.(*PayloadTypeError); !ok || e.Event.ID != unencodable.ID || e.Event.Payload != nil || e.Expected != "int" {
+		t.Errorf("expected the PayloadTypeError without the unencodable payload, got %#v", read.Payload)
+	}
+}
+
+// TestEventLogReopen ensures the log survives a restart, across segments.
+func TestEventLogReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	eventLog, err := OpenEventLog(dir, WithSegmentSize(256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := eventLog.Append(NewEvent("test-publisher", Info, "", i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_ = eventLog.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(segments) < 2 {
+		t.Fatalf("expected the log to be split into segments, got %d", len(segments))
+	}
+
+	eventLog, err = OpenEventLog(dir, WithSegmentSize(256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	if next := eventLog.NextOffset(); next != 21 {
+		t.Fatalf("expected next offset 21, got %d", next)
+	}
+
+	var payloads []interface{}
+	err = eventLog.Replay(5, func(event Event) error {
+		payloads = append(payloads, event.Payload)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payloads) != 16 || payloads[0] != 4 || payloads[15] != 19 {
+		t.Errorf("expected payloads 4 to 19, got %v", payloads)
+	}
+}
+
+// TestEventLogTornWrite ensures a partially written record is dropped on open.
+func TestEventLogTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	eventLog, _ := OpenEventLog(dir)
+	_, _ = eventLog.Append(InfoEvent("system", "first"))
+	_, _ = eventLog.Append(InfoEvent("system", "second"))
+	_ = eventLog.Close()
+
+	path := segmentPath(dir, 1, logSuffix)
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventLog, err := OpenEventLog(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	if next := eventLog.NextOffset(); next != 2 {
+		t.Fatalf("expected the torn record to be dropped, next offset is %d", next)
+	}
+	offset, err := eventLog.Append(InfoEvent("system", "third"))
+	if err != nil || offset != 2 {
+		t.Fatalf("expected to append at offset 2, got %d (%v)", offset, err)
+	}
+	if event, _ := eventLog.Read(2); event.Meta != "third" {
+		t.Errorf("expected the new record at offset 2, got %+v", event)
+	}
+}
+
+// TestEventLogStaleIndex ensures the index of an earlier segment is rebuilt when it
+// doesn't cover its data file.
+func TestEventLogStaleIndex(t *testing.T) {
+	dir := t.TempDir()
+
+	eventLog, _ := OpenEventLog(dir, WithSegmentSize(256))
+	for i := 0; i < 20; i++ {
+		_, _ = eventLog.Append(NewEvent("test-publisher", Info, "", i))
+	}
+	_ = eventLog.Close()
+
+	path := segmentPath(dir, 1, indexSuffix)
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-indexEntrySize); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventLog, err := OpenEventLog(dir, WithSegmentSize(256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	if next := eventLog.NextOffset(); next != 21 {
+		t.Fatalf("expected next offset 21, got %d", next)
+	}
+	for offset := uint64(1); offset <= 20; offset++ {
+		if event, err := eventLog.Read(offset); err != nil || event.Payload != int(offset-1) {
+			t.Fatalf("expected payload %d at offset %d, got %v (%v)", offset-1, offset, event.Payload, err)
+		}
+	}
+}
+
+// TestStreamerReplay ensures subscribers replay the logged history before live events.
+func TestStreamerReplay(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	streamer := NewStreamer(WithEventLog(eventLog))
+	for i := 0; i < 5; i++ {
+		streamer.Publish(NewEvent("test-publisher", Info, "", i))
+	}
+	streamer.Publish(ErrorEvent("test-publisher", "not replayed to Info subscribers"))
+
+	t.Run("From beginning", func(t *testing.T) {
+		ch, err := streamer.Subscribe("from-beginning", FromBeginning(), WithEventTypes(Info))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer streamer.Unsubscribe("from-beginning")
+
+		streamer.Publish(NewEvent("test-publisher", Info, "", 5))
+
+		for i := 0; i < 6; i++ {
+			select {
+			case event := <-ch:
+				if event.Payload != i {
+					t.Fatalf("expected payload %d, got %v", i, event.Payload)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for payload %d", i)
+			}
+		}
+	})
+
+	t.Run("From offset", func(t *testing.T) {
+		ch, err := streamer.Subscribe("from-offset", FromOffset(6))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer streamer.Unsubscribe("from-offset")
+
+		select {
+		case event := <-ch:
+			if event.Offset != 6 || event.Type != Error {
+				t.Fatalf("expected the Error event at offset 6, got %+v", event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the replayed event")
+		}
+	})
+
+	t.Run("Without event log", func(t *testing.T) {
+		if _, err := NewStreamer().Subscribe("replayer", FromBeginning()); !errors.Is(err, ErrNoEventLog) {
+			t.Errorf("expected ErrNoEventLog, got %v", err)
+		}
+	})
+}
@@ -1,6 +1,13 @@
 package sirkeji
 
-import "sync"
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
 
 // EventType represents the type of event.
 // Used to categorize and handle different kinds of events within the system.
@@ -9,18 +16,38 @@ type EventType string
 // Event represents an event in the system.
 //
 // Fields:
+//   - ID: The unique identifier of the event, assigned on creation or when published.
+//   - Time: The time the event was published.
+//   - Offset: The position of the event in the EventLog, zero if the event wasn't logged.
 //   - Publisher: The originator of the event (e.g., system or component name).
 //   - Type: The type of the event, defined by EventType.
 //   - Meta: Optional metadata describing the event.
 //   - Payload: Optional additional data associated with the event.
+//   - Headers: Optional string key-value pairs carried along with the event.
+//   - CorrelationID: The ID of the first event in the chain of events this event belongs to.
+//   - CausationID: The ID of the event this event was published in reaction to.
+//
+// Events are linked into chains with Derive, or by publishing them with PublishContext and
+// the context given to a ContextSubscriber. A NewEvent published with Publish from a plain
+// Subscriber's Process starts a new chain, as the Streamer can't tell which event it reacts to.
 type Event struct {
-	Publisher string
-	Type      EventType
-	Meta      string
-	Payload   interface{}
+	ID            string
+	Time          time.Time
+	Offset        uint64
+	Publisher     string
+	Type          EventType
+	Meta          string
+	Payload       interface{}
+	Headers       map[string]string
+	CorrelationID string
+	CausationID   string
 }
 
 // NewEvent creates a new Event with the required fields.
+//
+// The event is stamped with a new unique ID. Its Time and CorrelationID are set when it
+// is published: it starts a new correlation chain, unless it is published with the
+// context of a ContextSubscriber's ProcessContext (see ContextWithCause).
 func NewEvent(publisher string, eventType EventType, meta string, payload interface{}) Event {
 	if publisher == "" {
 		panic("event must have a non-empty publisher")
@@ -29,6 +56,7 @@ func NewEvent(publisher string, eventType EventType, meta string, payload interf
 		panic("event must have a non-empty type")
 	}
 	return Event{
+		ID:        newEventID(),
 		Publisher: publisher,
 		Type:      eventType,
 		Meta:      meta,
@@ -36,6 +64,121 @@ func NewEvent(publisher string, eventType EventType, meta string, payload interf
 	}
 }
 
+// Derive creates a new Event published in reaction to this event.
+//
+// The derived event gets its own ID, inherits the CorrelationID of this event and
+// records this event's ID as its CausationID, so chains of events can be traced.
+// Publishing with the context of a ContextSubscriber links events the same way.
+//
+// Parameters:
+//   - publisher: The origin of the new event.
+//   - eventType: The type of the new event.
+//   - meta: Optional metadata describing the new event.
+//   - payload: Optional data associated with the new event.
+//
+// Returns:
+//   - A new Event caused by this event.
+//
+// Example:
+//
+//	func (p *Publisher) Process(event sirkeji.Event) {
+//	    p.publish(event.Derive(p.uid, SquaredNumber, "", n*n))
+//	}
+func (e Event) Derive(publisher string, eventType EventType, meta string, payload interface{}) Event {
+	return NewEvent(publisher, eventType, meta, payload).causedBy(e)
+}
+
+// causedBy returns a copy of the event linked to the event it was published in reaction to.
+func (e Event) causedBy(cause Event) Event {
+	e.CorrelationID = cause.CorrelationID
+	if e.CorrelationID == "" {
+		e.CorrelationID = cause.ID
+	}
+	e.CausationID = cause.ID
+	return e
+}
+
+// causeKey is the context key of the event carried by ContextWithCause.
+type causeKey struct{}
+
+// ContextWithCause returns a copy of ctx carrying the event being processed.
+//
+// Events published with PublishContext and the returned context are linked to the
+// cause like with Event.Derive, unless they already have a CorrelationID or a
+// CausationID. The SubscriptionManager passes such a context to ContextSubscribers.
+//
+// Example:
+//
+//	ctx := sirkeji.ContextWithCause(context.Background(), event)
+//	_ = streamer.PublishContext(ctx, sirkeji.NewEvent("billing", InvoiceCreated, "", invoice))
+func ContextWithCause(ctx context.Context, cause Event) context.Context {
+	return context.WithValue(ctx, causeKey{}, cause)
+}
+
+// CauseFromContext returns the event carried by ctx, see ContextWithCause.
+//
+// Returns:
+//   - The event being processed and true, or false if ctx carries no event.
+func CauseFromContext(ctx context.Context) (Event, bool) {
+	cause, ok := ctx.Value(causeKey{}).(Event)
+	return cause, ok
+}
+
+// inheritCause links the event to the event carried by ctx, if any, unless it already
+// belongs to a correlation chain.
+func inheritCause(ctx context.Context, event Event) Event {
+	cause, ok := CauseFromContext(ctx)
+	if !ok || event.CorrelationID != "" || event.CausationID != "" {
+		return event
+	}
+	return event.causedBy(cause)
+}
+
+// WithHeader returns a copy of the event with the given header set.
+//
+// The Headers map of the original event is left untouched.
+//
+// Example:
+//
+//	event := InfoEvent("api", "request served").WithHeader("request-id", requestID)
+func (e Event) WithHeader(key, value string) Event {
+	headers := make(map[string]string, len(e.Headers)+1)
+	for k, v := range e.Headers {
+		headers[k] = v
+	}
+	headers[key] = value
+	e.Headers = headers
+	return e
+}
+
+// Header returns the value of the given header, or an empty string if it isn't set.
+func (e Event) Header(key string) string {
+	return e.Headers[key]
+}
+
+// stampEvent assigns an ID, a timestamp and a CorrelationID to the event where missing.
+func stampEvent(event Event) Event {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = event.ID
+	}
+	return event
+}
+
+// newEventID generates a random, RFC 4122 version 4 UUID.
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
 // Predefined EventTypes represent commonly used event categories.
 const (
 	// Error represents an error event, typically used to signal an issue.
@@ -46,6 +189,14 @@ const (
 
 	// Shutdown represents a shutdown event, used during application termination.
 	Shutdown EventType = "Shutdown"
+
+	// Reply represents a reply to a request event, see Requester and Respond.
+	// It is namespaced, so applications can still register a "Reply" EventType of their own.
+	Reply EventType = "sirkeji.Reply"
+
+	// DeadLetter represents an event that failed processing, see DeadLetterRecord.
+	// It is namespaced like Reply, so applications can still register a "DeadLetter" EventType of their own.
+	DeadLetter EventType = "sirkeji.DeadLetter"
 )
 
 // InfoEvent creates an informational event.
@@ -57,7 +208,7 @@ const (
 //   - message: A descriptive message about the event.
 //
 // Returns:
-//   - An Event instance with the `Info` EventType.
+//   - An Event instance with the `Info` EventType, stamped with a new ID.
 //
 // Example:
 //
@@ -65,6 +216,7 @@ const (
 //	gStreamer.Publish(event)
 func InfoEvent(publisher, message string) Event {
 	return Event{
+		ID:        newEventID(),
 		Publisher: publisher,
 		Type:      Info,
 		Meta:      message,
@@ -80,7 +232,7 @@ func InfoEvent(publisher, message string) Event {
 //   - message: A descriptive message about the error.
 //
 // Returns:
-//   - An Event instance with the `Error` EventType.
+//   - An Event instance with the `Error` EventType, stamped with a new ID.
 //
 // Example:
 //
@@ -88,6 +240,7 @@ func InfoEvent(publisher, message string) Event {
 //	gStreamer.Publish(event)
 func ErrorEvent(publisher, message string) Event {
 	return Event{
+		ID:        newEventID(),
 		Publisher: publisher,
 		Type:      Error,
 		Meta:      message,
@@ -95,36 +248,66 @@ func ErrorEvent(publisher, message string) Event {
 }
 
 // eventTypeRegistry is a thread-safe registry for EventTypes.
-// Ensures that each EventType is unique within the system.
+// Ensures that each EventType is unique within the system, and keeps track of
+// how the payloads of each EventType are encoded.
 var (
 	eventTypeRegistry = struct {
 		sync.RWMutex
-		types map[EventType]struct{}
-	}{types: map[EventType]struct{}{
-		Error:    {},
-		Info:     {},
-		Shutdown: {},
+		types map[EventType]eventTypeInfo
+	}{types: map[EventType]eventTypeInfo{
+		Error:      {},
+		Info:       {},
+		Shutdown:   {},
+		Reply:      {},
+		DeadLetter: {},
 	}}
 )
 
+// eventTypeInfo describes the payload of a registered EventType.
+type eventTypeInfo struct {
+	// payloadType is the Go type payloads are decoded into, nil for untyped payloads.
+	payloadType reflect.Type
+	// codec encodes and decodes payloads, nil if payloads can't be encoded.
+	codec Codec
+}
+
+// EventTypeOption configures an EventType registered with RegisterEventType.
+type EventTypeOption func(*eventTypeInfo)
+
 // RegisterEventType registers a new EventType to ensure uniqueness.
 //
 // Panics if the EventType is already registered, preventing duplication.
 //
 // Parameters:
 //   - eventType: The EventType to register.
+//   - opts: Optional EventTypeOptions, e.g. WithPayload to make its payloads encodable.
 //
 // Example:
 //
 //	RegisterEventType("CustomEvent")
-func RegisterEventType(eventType EventType) {
+//	RegisterEventType("OrderPlaced", WithPayload(Order{}, JSONCodec))
+func RegisterEventType(eventType EventType, opts ...EventTypeOption) {
+	info := eventTypeInfo{}
+	for _, opt := range opts {
+		opt(&info)
+	}
+
 	eventTypeRegistry.Lock()
 	defer eventTypeRegistry.Unlock()
 
 	if _, exists := eventTypeRegistry.types[eventType]; exists {
 		panic("duplicate event type registration: " + string(eventType))
 	}
-	eventTypeRegistry.types[eventType] = struct{}{}
+	eventTypeRegistry.types[eventType] = info
+}
+
+// lookupEventType returns the registration of an EventType.
+func lookupEventType(eventType EventType) (eventTypeInfo, bool) {
+	eventTypeRegistry.RLock()
+	defer eventTypeRegistry.RUnlock()
+
+	info, exists := eventTypeRegistry.types[eventType]
+	return info, exists
 }
 
 // IsEventTypeRegistered checks if an EventType is already registered.
@@ -1,7 +1,9 @@
 package sirkeji
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 // TestNewEvent ensures NewEvent correctly constructs an Event and handles invalid inputs.
@@ -98,6 +100,16 @@ func TestRegisterEventType(t *testing.T) {
 		}()
 		RegisterEventType(customEventType)
 	})
+
+	t.Run("Library EventTypes Are Namespaced", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("expected 'Reply' and 'DeadLetter' to be free for applications, got %v", r)
+			}
+		}()
+		RegisterEventType("Reply")
+		RegisterEventType("DeadLetter")
+	})
 }
 
 // TestIsEventTypeRegistered ensures IsEventTypeRegistered behaves as expected.
@@ -112,6 +124,9 @@ func TestIsEventTypeRegistered(t *testing.T) {
 		if !IsEventTypeRegistered(Shutdown) {
 			t.Errorf("expected EventType 'Shutdown' to be registered")
 		}
+		if !IsEventTypeRegistered(Reply) {
+			t.Errorf("expected EventType 'Reply' to be registered")
+		}
 	})
 
 	t.Run("Unregistered EventType", func(t *testing.T) {
@@ -120,3 +135,121 @@ func TestIsEventTypeRegistered(t *testing.T) {
 		}
 	})
 }
+
+// TestEventEnvelope ensures events are stamped with an ID, a timestamp and a correlation chain.
+func TestEventEnvelope(t *testing.T) {
+	t.Run("Constructors stamp an ID", func(t *testing.T) {
+		for _, event := range []Event{
+			NewEvent("test-publisher", Info, "metadata", nil),
+			InfoEvent("system", "Application started"),
+			ErrorEvent("database", "Connection failed"),
+		} {
+			if event.ID == "" {
+				t.Errorf("expected a non-empty ID for %s event", event.Type)
+			}
+			if !event.Time.IsZero() || event.CorrelationID != "" {
+				t.Errorf("expected the Time and CorrelationID to be left to Publish, got %+v", event)
+			}
+		}
+	})
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		seen := map[string]struct{}{}
+		for i := 0; i < 1000; i++ {
+			id := NewEvent("test-publisher", Info, "", nil).ID
+			if _, ok := seen[id]; ok {
+				t.Fatalf("duplicate event ID %s", id)
+			}
+			seen[id] = struct{}{}
+		}
+	})
+
+	t.Run("Publish stamps missing fields", func(t *testing.T) {
+		streamer := NewStreamer()
+		ch, _ := streamer.Subscribe("user123")
+
+		streamer.Publish(Event{Publisher: "system", Type: Info})
+
+		received := <-ch
+		if received.ID == "" || received.Time.IsZero() || received.CorrelationID != received.ID {
+			t.Errorf("expected the published event to be stamped, got %+v", received)
+		}
+
+		before := time.Now()
+		event := InfoEvent("system", "created before being published")
+		time.Sleep(10 * time.Millisecond)
+		streamer.Publish(event)
+
+		if received := <-ch; received.Time.Sub(before) < 10*time.Millisecond {
+			t.Errorf("expected the publish time, got %v for an event created at %v", received.Time, before)
+		}
+	})
+
+	t.Run("Publishing with the context of a cause propagates correlation and causation", func(t *testing.T) {
+		streamer := NewStreamer()
+		ch, _ := streamer.Subscribe("user123", WithEventTypes(Error))
+		subscribeWith(t, streamer, &reactingSubscriber{MockSubscriber: NewMockSubscriber("reactor"), streamer: streamer})
+
+		root := InfoEvent("system", "root")
+		streamer.Publish(root)
+
+		var reaction Event
+		select {
+		case reaction = <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("expected the subscriber to react to the event")
+		}
+		if reaction.CorrelationID != root.ID || reaction.CausationID != root.ID {
+			t.Errorf("expected the reaction to be caused by %s, got %+v", root.ID, reaction)
+		}
+
+		derived := root.Derive("system", Info, "", nil)
+		ctx := ContextWithCause(context.Background(), InfoEvent("system", "other"))
+		if linked := inheritCause(ctx, derived); linked.CausationID != root.ID {
+			t.Errorf("expected events already linked to keep their cause, got %+v", linked)
+		}
+	})
+
+	t.Run("Derive propagates correlation and causation", func(t *testing.T) {
+		root := NewEvent("root", Info, "", nil)
+		child := root.Derive("child", Info, "", nil)
+		grandchild := child.Derive("grandchild", Error, "", nil)
+
+		if child.ID == root.ID {
+			t.Error("expected the derived event to have its own ID")
+		}
+		if child.CorrelationID != root.ID || grandchild.CorrelationID != root.ID {
+			t.Errorf("expected CorrelationID '%s', got '%s' and '%s'", root.ID, child.CorrelationID, grandchild.CorrelationID)
+		}
+		if child.CausationID != root.ID {
+			t.Errorf("expected CausationID '%s', got '%s'", root.ID, child.CausationID)
+		}
+		if grandchild.CausationID != child.ID {
+			t.Errorf("expected CausationID '%s', got '%s'", child.ID, grandchild.CausationID)
+		}
+	})
+
+	t.Run("WithHeader copies headers", func(t *testing.T) {
+		original := InfoEvent("system", "").WithHeader("a", "1")
+		updated := original.WithHeader("b", "2")
+
+		if original.Header("b") != "" {
+			t.Error("expected the original event headers to be left untouched")
+		}
+		if updated.Header("a") != "1" || updated.Header("b") != "2" {
+			t.Errorf("expected both headers to be set, got %v", updated.Headers)
+		}
+	})
+}
+
+// reactingSubscriber publishes an Error event in reaction to every Info event.
+type reactingSubscriber struct {
+	*MockSubscriber
+	streamer Streamer
+}
+
+func (rs *reactingSubscriber) ProcessContext(ctx context.Context, event Event) {
+	if event.Type == Info {
+		_ = rs.streamer.PublishContext(ctx, ErrorEvent(rs.Uid(), "reaction"))
+	}
+}
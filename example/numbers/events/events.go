@@ -3,11 +3,18 @@ package events
 import "github.com/thisiscetin/sirkeji"
 
 var (
-	Number            sirkeji.EventType = "Number"
-	SquaredNumber     sirkeji.EventType = "SquaredNumber"
-	NumberCountUpdate sirkeji.EventType = "NumberCountUpdate"
+	Number            = sirkeji.DefineEvent[int]("Number")
+	SquaredNumber     = sirkeji.DefineEvent[int]("SquaredNumber")
+	NumberCountUpdate = sirkeji.DefineEvent[int]("NumberCountUpdate")
+)
+
+// Scheduled events, published periodically by the scheduler.
+const (
+	GenerateNumber    sirkeji.EventType = "GenerateNumber"
+	ReportNumberCount sirkeji.EventType = "ReportNumberCount"
 )
 
 func init() {
-	sirkeji.RegisterEventTypes(Number, SquaredNumber, NumberCountUpdate)
+	sirkeji.RegisterEventType(GenerateNumber)
+	sirkeji.RegisterEventType(ReportNumberCount)
 }
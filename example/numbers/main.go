@@ -2,27 +2,45 @@ package main
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/thisiscetin/sirkeji"
+	"github.com/thisiscetin/sirkeji/example/numbers/events"
 	"github.com/thisiscetin/sirkeji/example/numbers/number"
 	"github.com/thisiscetin/sirkeji/example/numbers/number_count"
 	"github.com/thisiscetin/sirkeji/example/numbers/squared_number"
 )
 
-var (
-	gStreamer = sirkeji.NewStreamer()
-)
-
 func main() {
 	gCtx := context.Background()
 	terminationDelay := time.Second * 5
 
+	// Events are logged, so number counts survive restarts.
+	eventLog, err := sirkeji.OpenEventLog("numbers-events")
+	if err != nil {
+		log.Fatalf("failed to open event log: %v", err)
+	}
+	defer eventLog.Close()
+
+	gStreamer := sirkeji.NewStreamer(sirkeji.WithEventLog(eventLog))
+
 	sirkeji.Subscribe(gStreamer, sirkeji.NewLogger())
 	sirkeji.Subscribe(gStreamer, number.NewPublisher("number-publisher-1", gStreamer.Publish))
 	sirkeji.Subscribe(gStreamer, number.NewPublisher("number-publisher-2", gStreamer.Publish))
 	sirkeji.Subscribe(gStreamer, squared_number.NewPublisher("squared-number-publisher-1", gStreamer.Publish))
-	sirkeji.Subscribe(gStreamer, number_count.NewPublisher("number-count-publisher-1", gStreamer.Publish))
+	sirkeji.Subscribe(gStreamer, number_count.NewPublisher("number-count-publisher-1", gStreamer.Publish),
+		sirkeji.WithSubscribeOptions(sirkeji.FromBeginning()))
+
+	// Periodic work is triggered by scheduled events rather than tickers in the components.
+	scheduler, err := sirkeji.NewScheduler(gStreamer)
+	if err != nil {
+		log.Fatalf("failed to create scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	_, _ = scheduler.Every(2*time.Second, sirkeji.NewEvent("main", events.GenerateNumber, "", nil))
+	_, _ = scheduler.Every(5*time.Second, sirkeji.NewEvent("main", events.ReportNumberCount, "", nil))
 
 	sirkeji.WaitForTermination(gCtx, gStreamer, terminationDelay)
 }
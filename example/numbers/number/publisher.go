@@ -5,7 +5,6 @@ import (
 	"github.com/thisiscetin/sirkeji/example/numbers/events"
 	"math/rand/v2"
 	"strconv"
-	"time"
 )
 
 type Publisher struct {
@@ -17,23 +16,18 @@ func (p *Publisher) Uid() string {
 	return p.uid
 }
 
-func (p *Publisher) Process(event sirkeji.Event) {}
-
-func (p *Publisher) Subscribed() {
-	go func() {
-		for range time.Tick(time.Second * 2) {
-			n := rand.IntN(1_000)
-
-			p.publish(sirkeji.Event{
-				Publisher: p.uid,
-				Type:      events.Number,
-				Meta:      strconv.Itoa(n),
-				Payload:   n,
-			})
-		}
-	}()
+func (p *Publisher) EventTypes() []sirkeji.EventType {
+	return []sirkeji.EventType{events.GenerateNumber}
 }
 
+func (p *Publisher) Process(event sirkeji.Event) {
+	n := rand.IntN(1_000)
+
+	p.publish(event.Derive(p.uid, events.Number.Type(), strconv.Itoa(n), n))
+}
+
+func (p *Publisher) Subscribed() {}
+
 func (p *Publisher) Unsubscribed() {}
 
 func NewPublisher(uid string, publish func(e sirkeji.Event)) *Publisher {
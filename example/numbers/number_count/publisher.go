@@ -12,6 +12,11 @@ type Publisher struct {
 	uid     string
 	publish func(e sirkeji.Event)
 
+	// clock tells the time the publisher was subscribed at.
+	clock sirkeji.Clock
+	// started is used to skip the report requests replayed from previous runs.
+	started time.Time
+
 	count int
 	sync.RWMutex
 }
@@ -20,28 +25,38 @@ func (p *Publisher) Uid() string {
 	return p.uid
 }
 
+func (p *Publisher) EventTypes() []sirkeji.EventType {
+	return []sirkeji.EventType{events.Number.Type(), events.ReportNumberCount}
+}
+
 func (p *Publisher) Process(event sirkeji.Event) {
-	if event.Type == events.Number {
-		p.Lock()
-		defer p.Unlock()
+	if event.Type == events.ReportNumberCount {
+		p.RLock()
+		defer p.RUnlock()
 
-		p.count++
+		if event.Time.Before(p.started) {
+			return
+		}
+
+		p.publish(event.Derive(p.uid, events.NumberCountUpdate.Type(), strconv.Itoa(p.count), p.count))
+		return
 	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.count++
+}
+
+func (p *Publisher) SetClock(clock sirkeji.Clock) {
+	p.clock = clock
 }
 
 func (p *Publisher) Subscribed() {
-	go func() {
-		for range time.Tick(5 * time.Second) {
-			p.RLock()
-			p.publish(sirkeji.Event{
-				Publisher: p.uid,
-				Type:      events.NumberCountUpdate,
-				Meta:      strconv.Itoa(p.count),
-				Payload:   p.count,
-			})
-			p.RUnlock()
-		}
-	}()
+	p.Lock()
+	defer p.Unlock()
+
+	p.started = p.clock.Now()
 }
 
 func (p *Publisher) Unsubscribed() {}
@@ -50,5 +65,6 @@ func NewPublisher(uid string, publish func(e sirkeji.Event)) *Publisher {
 	return &Publisher{
 		uid:     uid,
 		publish: publish,
+		clock:   sirkeji.SystemClock,
 	}
 }
@@ -15,18 +15,19 @@ func (p *Publisher) Uid() string {
 	return p.uid
 }
 
+func (p *Publisher) EventTypes() []sirkeji.EventType {
+	return []sirkeji.EventType{events.Number.Type()}
+}
+
 func (p *Publisher) Process(event sirkeji.Event) {
-	if event.Type == events.Number {
-		n := event.Payload.(int)
-		nSquare := n * n
-
-		p.publish(sirkeji.Event{
-			Publisher: p.uid,
-			Type:      events.SquaredNumber,
-			Meta:      strconv.Itoa(nSquare),
-			Payload:   nSquare,
-		})
+	n, err := events.Number.Payload(event)
+	if err != nil {
+		p.publish(event.Derive(p.uid, sirkeji.Error, err.Error(), err))
+		return
 	}
+	nSquare := n * n
+
+	p.publish(event.Derive(p.uid, events.SquaredNumber.Type(), strconv.Itoa(nSquare), nSquare))
 }
 
 func (p *Publisher) Subscribed() {}
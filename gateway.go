@@ -0,0 +1,380 @@
+package sirkeji
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxMessageSize bounds the messages received by WebSocketGateways created
+// without WithMaxMessageSize.
+const DefaultMaxMessageSize = 1 << 20
+
+// DefaultWriteTimeout bounds the time WebSocketGateways created without WithWriteTimeout
+// spend writing a message to a client.
+const DefaultWriteTimeout = 10 * time.Second
+
+// ErrNotPublishable is returned when a gateway client publishes an event whose
+// EventType isn't in the allowlist of its connection.
+var ErrNotPublishable = errors.New("event type is not publishable")
+
+// WebSocketGateway lets WebSocket clients, e.g. browser UIs, subscribe to the events of
+// a Streamer and publish events to it.
+//
+// Every connection is a Subscriber managed by its own SubscriptionManager, processing
+// events sequentially so clients receive them in order. Connections queue events with
+// OverflowDropOldest, and clients that stop reading are disconnected once a message
+// can't be written within the write timeout, so a slow client never blocks publishers
+// (see WithWriteTimeout). Clients exchange JSON messages
+// with the gateway, each carrying an action and an optional id the gateway echoes in
+// its reply:
+//
+//	→ {"action":"subscribe","id":"1","types":["orders.>"]}   // no types for every event
+//	← {"action":"subscribed","id":"1"}
+//	← {"action":"event","event":{"id":"...","type":"orders.created","publisher":"checkout","payload":{...}}}
+//	→ {"action":"publish","id":"2","event":{"type":"cart.updated","payload":{...}}}
+//	← {"action":"published","id":"2","event":{"id":"...","type":"cart.updated",...}}
+//	→ {"action":"unsubscribe","id":"3"}
+//	← {"action":"unsubscribed","id":"3"}
+//
+// Failed actions are answered with {"action":"error","id":...,"error":"..."}. Subscribing
+// again replaces the types of the subscription.
+//
+// Clients may only publish the EventTypes allowed for their connection (see
+// WithPublishableTypes and WithPublishAllowlist), nothing by default. Published events
+// get a new ID and the UID of the connection as Publisher, their payload being decoded
+// into the payload type bound to their EventType (see DefineEvent).
+//
+// The gateway implements the WebSocket protocol of RFC 6455 with the standard library,
+// without extensions or subprotocols.
+type WebSocketGateway struct {
+	// streamer is the Streamer clients subscribe and publish to.
+	streamer Streamer
+	// uid prefixes the UIDs of the connections, which are uid-1, uid-2, ...
+	uid string
+	// allowlist returns the EventTypes a connection may publish.
+	allowlist func(r *http.Request) []EventType
+	// checkOrigin accepts or rejects handshakes by their origin.
+	checkOrigin func(r *http.Request) bool
+	// managerOpts configure the SubscriptionManagers of the connections.
+	managerOpts []ManagerOption
+	// maxMessageSize bounds the messages received from clients.
+	maxMessageSize int64
+	// writeTimeout bounds the time spent writing a message to a client.
+	writeTimeout time.Duration
+
+	// conns counts the connections ever accepted, it numbers their UIDs.
+	conns atomic.Uint64
+}
+
+// gatewayMessage is a message exchanged between a WebSocketGateway and its clients.
+type gatewayMessage struct {
+	Action string      `json:"action"`
+	ID     string      `json:"id,omitempty"`
+	Types  []EventType `json:"types,omitempty"`
+	Event  *eventJSON  `json:"event,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// GatewayOption configures a WebSocketGateway created by NewWebSocketGateway.
+type GatewayOption func(*WebSocketGateway)
+
+// WithPublishableTypes allows every connection to publish the given EventTypes, which
+// may be patterns like "cart.>" (see MatchEventType).
+//
+// Example:
+//
+//	gateway, err := NewWebSocketGateway(streamer, "ui", WithPublishableTypes("cart.>", "checkout.requested"))
+func WithPublishableTypes(types ...EventType) GatewayOption {
+	return WithPublishAllowlist(func(*http.Request) []EventType {
+		return types
+	})
+}
+
+// WithPublishAllowlist decides, from the handshake request, the EventTypes a connection
+// may publish, which may be patterns like "cart.>" (see MatchEventType).
+//
+// Example:
+//
+//	gateway, err := NewWebSocketGateway(streamer, "ui", WithPublishAllowlist(func(r *http.Request) []EventType {
+//	    if isAdmin(r) {
+//	        return []EventType{">"}
+//	    }
+//	    return []EventType{"cart.>"}
+//	}))
+func WithPublishAllowlist(allowlist func(r *http.Request) []EventType) GatewayOption {
+	return func(g *WebSocketGateway) {
+		g.allowlist = allowlist
+	}
+}
+
+// WithOriginCheck sets the function accepting or rejecting handshakes by their Origin
+// header, protecting against cross-site WebSocket hijacking.
+//
+// Without this option only handshakes without an Origin header, or from the origin of
+// the gateway's host, are accepted.
+//
+// Example:
+//
+//	gateway, err := NewWebSocketGateway(streamer, "ui", WithOriginCheck(func(r *http.Request) bool {
+//	    return r.Header.Get("Origin") == "https://app.example.com"
+//	}))
+func WithOriginCheck(check func(r *http.Request) bool) GatewayOption {
+	return func(g *WebSocketGateway) {
+		g.checkOrigin = check
+	}
+}
+
+// WithGatewayManagerOptions configures the SubscriptionManagers of the connections,
+// e.g. with WithSubscribeOptions or WithProcessMiddleware.
+//
+// Connections are subscribed with OverflowDropOldest unless another policy is given.
+//
+// Example:
+//
+//	gateway, err := NewWebSocketGateway(streamer, "ui", WithGatewayManagerOptions(
+//	    WithSubscribeOptions(WithQueueSize(64), WithOverflowPolicy(OverflowDropOldest))))
+func WithGatewayManagerOptions(opts ...ManagerOption) GatewayOption {
+	return func(g *WebSocketGateway) {
+		g.managerOpts = append(g.managerOpts, opts...)
+	}
+}
+
+// WithMaxMessageSize bounds the messages received from clients, connections sending
+// larger messages are closed. DefaultMaxMessageSize is used without this option.
+func WithMaxMessageSize(size int64) GatewayOption {
+	return func(g *WebSocketGateway) {
+		g.maxMessageSize = size
+	}
+}
+
+// WithWriteTimeout bounds the time spent writing a message to a client, clients that
+// don't read their messages in time are disconnected. DefaultWriteTimeout is used
+// without this option.
+func WithWriteTimeout(timeout time.Duration) GatewayOption {
+	return func(g *WebSocketGateway) {
+		g.writeTimeout = timeout
+	}
+}
+
+// NewWebSocketGateway creates a WebSocketGateway for the Streamer.
+//
+// Parameters:
+//   - streamer: The Streamer clients subscribe and publish to. Must not be nil.
+//   - uid: The unique identifier of the gateway, prefixing the UIDs of its connections.
+//   - opts: Optional GatewayOptions.
+//
+// Returns:
+//   - A pointer to a new WebSocketGateway.
+//   - ErrStreamerShouldNotBeNil if the streamer is nil.
+//
+// Example:
+//
+//	gateway, err := NewWebSocketGateway(streamer, "ui", WithPublishableTypes("cart.>"))
+//	if err != nil {
+//	    log.Fatalf("failed to create gateway: %v", err)
+//	}
+//	http.Handle("/ws", gateway)
+func NewWebSocketGateway(streamer Streamer, uid string, opts ...GatewayOption) (*WebSocketGateway, error) {
+	if streamer == nil {
+		return nil, ErrStreamerShouldNotBeNil
+	}
+
+	g := &WebSocketGateway{
+		streamer:       streamer,
+		uid:            uid,
+		allowlist:      func(*http.Request) []EventType { return nil },
+		checkOrigin:    sameOrigin,
+		maxMessageSize: DefaultMaxMessageSize,
+		writeTimeout:   DefaultWriteTimeout,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// sameOrigin accepts requests without an Origin header or from the origin of their host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves the client until
+// it disconnects.
+func (g *WebSocketGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !g.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	ws, err := acceptWebSocket(w, r, g.maxMessageSize, g.writeTimeout)
+	if err != nil {
+		return
+	}
+
+	c := &gatewayConn{
+		uid:     fmt.Sprintf("%s-%d", g.uid, g.conns.Add(1)),
+		ws:      ws,
+		allowed: g.allowlist(r),
+	}
+	opts := append([]ManagerOption{
+		WithSequentialProcessing(),
+		WithSubscribeOptions(WithOverflowPolicy(OverflowDropOldest)),
+	}, g.managerOpts...)
+	manager, _ := NewSubscriptionManager(g.streamer, c, opts...)
+
+	subscribed := false
+	defer func() {
+		if subscribed {
+			manager.Unsubscribe()
+		}
+	}()
+
+	for {
+		op, data, err := ws.readMessage()
+		switch {
+		case errors.Is(err, ErrMessageTooLarge):
+			ws.close(wsCloseTooBig, "message too large")
+			return
+		case errors.Is(err, ErrWebSocketProtocol):
+			ws.close(wsCloseProtocolError, "protocol error")
+			return
+		case err != nil:
+			ws.close(wsCloseNormal, "")
+			return
+		}
+
+		var msg gatewayMessage
+		if op != wsText {
+			c.reply(gatewayMessage{Action: "error", Error: "only text messages are supported"})
+			continue
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.reply(gatewayMessage{Action: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			if subscribed {
+				manager.Unsubscribe()
+			}
+			c.setTypes(msg.Types)
+			if err := manager.Subscribe(); err != nil {
+				subscribed = false
+				c.reply(gatewayMessage{Action: "error", ID: msg.ID, Error: err.Error()})
+				continue
+			}
+			subscribed = true
+			c.reply(gatewayMessage{Action: "subscribed", ID: msg.ID})
+		case "unsubscribe":
+			if subscribed {
+				manager.Unsubscribe()
+				subscribed = false
+			}
+			c.reply(gatewayMessage{Action: "unsubscribed", ID: msg.ID})
+		case "publish":
+			event, err := c.event(msg.Event)
+			if err == nil {
+				err = g.streamer.PublishContext(r.Context(), event)
+			}
+			if err != nil {
+				c.reply(gatewayMessage{Action: "error", ID: msg.ID, Error: err.Error()})
+				continue
+			}
+			published := newEventJSON(event)
+			c.reply(gatewayMessage{Action: "published", ID: msg.ID, Event: &published})
+		default:
+			c.reply(gatewayMessage{Action: "error", ID: msg.ID, Error: fmt.Sprintf("unknown action %q", msg.Action)})
+		}
+	}
+}
+
+// gatewayConn is the Subscriber of a WebSocketGateway connection.
+type gatewayConn struct {
+	uid string
+	ws  *wsConn
+	// allowed holds the patterns of the EventTypes the client may publish.
+	allowed []EventType
+	// types holds the EventTypes the client subscribed to.
+	types []EventType
+	mu    sync.Mutex
+}
+
+func (c *gatewayConn) Uid() string {
+	return c.uid
+}
+
+// Process sends the event to the client.
+func (c *gatewayConn) Process(event Event) {
+	ej := newEventJSON(event)
+	c.reply(gatewayMessage{Action: "event", Event: &ej})
+}
+
+func (c *gatewayConn) Subscribed() {}
+
+func (c *gatewayConn) Unsubscribed() {}
+
+// EventTypes implements FilteredSubscriber with the types the client subscribed to.
+func (c *gatewayConn) EventTypes() []EventType {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.types
+}
+
+// setTypes sets the types the client subscribes to.
+func (c *gatewayConn) setTypes(types []EventType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.types = types
+}
+
+// reply sends the message to the client, closing the connection if it can't be written.
+func (c *gatewayConn) reply(msg gatewayMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		data, _ = json.Marshal(gatewayMessage{Action: "error", ID: msg.ID, Error: err.Error()})
+	}
+	if err := c.ws.writeText(data); err != nil {
+		_ = c.ws.conn.Close()
+	}
+}
+
+// event returns the event the client asked to publish.
+func (c *gatewayConn) event(ej *eventJSON) (Event, error) {
+	if ej == nil || ej.Type == "" {
+		return Event{}, errors.New("publish requires an event with a type")
+	}
+	if !slices.ContainsFunc(c.allowed, func(pattern EventType) bool {
+		return MatchEventType(pattern, ej.Type)
+	}) {
+		return Event{}, fmt.Errorf("%w: %s", ErrNotPublishable, ej.Type)
+	}
+
+	payload, err := ej.payload()
+	if err != nil {
+		return Event{}, err
+	}
+	return stampEvent(Event{
+		Publisher:     c.uid,
+		Type:          ej.Type,
+		Meta:          ej.Meta,
+		Payload:       payload,
+		Headers:       ej.Headers,
+		CorrelationID: ej.CorrelationID,
+		CausationID:   ej.CausationID,
+	}), nil
+}
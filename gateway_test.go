@@ -0,0 +1,240 @@
+package sirkeji
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gatewayClient is a WebSocket client of a WebSocketGateway.
+type gatewayClient struct {
+	*wsConn
+}
+
+func newTestGateway(t *testing.T, streamer Streamer, opts ...GatewayOption) *httptest.Server {
+	t.Helper()
+
+	gateway, err := NewWebSocketGateway(streamer, "ui", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(gateway)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dialGateway performs the WebSocket handshake with the server, returning the response.
+func dialGateway(t *testing.T, server *httptest.Server, header http.Header) (*gatewayClient, *http.Response) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("failed to send the handshake: %v", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatalf("failed to read the handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != websocketAccept("dGhlIHNhbXBsZSBub25jZQ==") {
+		t.Fatalf("unexpected Sec-WebSocket-Accept %q", accept)
+	}
+	return &gatewayClient{&wsConn{conn: conn, br: br, client: true}}, resp
+}
+
+func (c *gatewayClient) send(t *testing.T, msg string) {
+	t.Helper()
+
+	if err := c.writeText([]byte(msg)); err != nil {
+		t.Fatalf("failed to send %s: %v", msg, err)
+	}
+}
+
+func (c *gatewayClient) receive(t *testing.T) gatewayMessage {
+	t.Helper()
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := c.readMessage()
+	if err != nil {
+		t.Fatalf("failed to receive a message: %v", err)
+	}
+
+	var msg gatewayMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("invalid message %s: %v", data, err)
+	}
+	return msg
+}
+
+// TestGatewaySubscribe ensures clients receive the events of the types they subscribed to.
+func TestGatewaySubscribe(t *testing.T) {
+	streamer := NewStreamer()
+	client, _ := dialGateway(t, newTestGateway(t, streamer), nil)
+
+	client.send(t, `{"action":"subscribe","id":"1","types":["orders.>"]}`)
+	if reply := client.receive(t); reply.Action != "subscribed" || reply.ID != "1" {
+		t.Fatalf("expected a subscribed reply, got %+v", reply)
+	}
+
+	streamer.Publish(InfoEvent("checkout", "not an order"))
+	created := NewEvent("checkout", "orders.created", "", nil)
+	streamer.Publish(created)
+	if msg := client.receive(t); msg.Action != "event" || msg.Event.ID != created.ID {
+		t.Errorf("expected the orders.created event, got %+v", msg)
+	}
+
+	client.send(t, `{"action":"subscribe","id":"2","types":["Info"]}`)
+	client.receive(t)
+	info := InfoEvent("checkout", "resubscribed")
+	streamer.Publish(created)
+	streamer.Publish(info)
+	if msg := client.receive(t); msg.Action != "event" || msg.Event.ID != info.ID {
+		t.Errorf("expected the Info event after subscribing again, got %+v", msg)
+	}
+
+	client.send(t, `{"action":"unsubscribe","id":"3"}`)
+	if reply := client.receive(t); reply.Action != "unsubscribed" || reply.ID != "3" {
+		t.Errorf("expected an unsubscribed reply, got %+v", reply)
+	}
+}
+
+// TestGatewayPublish ensures clients publish the allowed types only, with typed payloads.
+func TestGatewayPublish(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("observer")
+	server := newTestGateway(t, streamer, WithPublishAllowlist(func(r *http.Request) []EventType {
+		if r.Header.Get("X-Role") == "admin" {
+			return []EventType{">"}
+		}
+		return []EventType{codecTestJSONOrder.Type()}
+	}))
+
+	user, _ := dialGateway(t, server, nil)
+	user.send(t, `{"action":"publish","id":"1","event":{"type":"CodecTestJSONOrder","meta":"from the UI","payload":{"ID":"order-1","Items":["tea"],"Total":1.5}}}`)
+	reply := user.receive(t)
+	if reply.Action != "published" || reply.ID != "1" || reply.Event == nil {
+		t.Fatalf("expected a published reply, got %+v", reply)
+	}
+
+	select {
+	case event := <-ch:
+		expected := codecTestOrder{ID: "order-1", Items: []string{"tea"}, Total: 1.5}
+		if event.ID != reply.Event.ID || event.Publisher != "ui-1" || event.Meta != "from the UI" {
+			t.Errorf("expected the published event, got %+v", event)
+		}
+		if !reflect.DeepEqual(event.Payload, expected) {
+			t.Errorf("expected payload %+v, got %#v", expected, event.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the event to be published")
+	}
+
+	user.send(t, `{"action":"publish","id":"2","event":{"type":"Shutdown"}}`)
+	if reply := user.receive(t); reply.Action != "error" || reply.ID != "2" || !strings.Contains(reply.Error, ErrNotPublishable.Error()) {
+		t.Errorf("expected ErrNotPublishable, got %+v", reply)
+	}
+
+	admin, _ := dialGateway(t, server, http.Header{"X-Role": {"admin"}})
+	admin.send(t, `{"action":"publish","id":"3","event":{"type":"Info","meta":"maintenance"}}`)
+	if reply := admin.receive(t); reply.Action != "published" {
+		t.Errorf("expected the admin to publish Info events, got %+v", reply)
+	}
+	if event := <-ch; event.Type != Info || event.Publisher != "ui-2" {
+		t.Errorf("expected the admin's Info event, got %+v", event)
+	}
+}
+
+// TestGatewayInvalidMessages ensures invalid messages are answered with errors.
+func TestGatewayInvalidMessages(t *testing.T) {
+	client, _ := dialGateway(t, newTestGateway(t, NewStreamer(), WithPublishableTypes(">")), nil)
+
+	for _, msg := range []string{
+		`not json`,
+		`{"action":"dance","id":"1"}`,
+		`{"action":"publish","id":"2"}`,
+		`{"action":"publish","id":"3","event":{"type":"CodecTestJSONOrder","payload":"not an order"}}`,
+	} {
+		client.send(t, msg)
+		if reply := client.receive(t); reply.Action != "error" || reply.Error == "" {
+			t.Errorf("expected an error for %s, got %+v", msg, reply)
+		}
+	}
+}
+
+// TestGatewayOrigin ensures handshakes from other origins are rejected by default.
+func TestGatewayOrigin(t *testing.T) {
+	server := newTestGateway(t, NewStreamer())
+
+	if _, resp := dialGateway(t, server, http.Header{"Origin": {"http://evil.example"}}); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a cross-origin handshake to be forbidden, got %d", resp.StatusCode)
+	}
+	if _, resp := dialGateway(t, server, http.Header{"Origin": {server.URL}}); resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected a same-origin handshake to be accepted, got %d", resp.StatusCode)
+	}
+}
+
+// TestGatewayDisconnect ensures connections are unsubscribed once the client disconnects.
+func TestGatewayDisconnect(t *testing.T) {
+	streamer := NewStreamer()
+	client, _ := dialGateway(t, newTestGateway(t, streamer), nil)
+	client.send(t, `{"action":"subscribe"}`)
+	client.receive(t)
+
+	if _, err := streamer.Subscribe("ui-1"); err == nil {
+		t.Fatal("expected the connection to be subscribed as ui-1")
+	}
+
+	client.close(wsCloseNormal, "")
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := streamer.Subscribe("ui-1"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the connection to be unsubscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestGatewaySlowClient ensures clients that stop reading don't block publishers.
+func TestGatewaySlowClient(t *testing.T) {
+	streamer := NewStreamer()
+	client, _ := dialGateway(t, newTestGateway(t, streamer, WithWriteTimeout(50*time.Millisecond)), nil)
+	client.send(t, `{"action":"subscribe"}`)
+	client.receive(t)
+
+	meta := strings.Repeat("a", 64<<10)
+	for i := 0; i < 500; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := streamer.PublishContext(ctx, InfoEvent("test", meta))
+		cancel()
+		if err != nil {
+			t.Fatalf("expected publishing to ignore the slow client, got %v", err)
+		}
+	}
+}
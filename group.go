@@ -0,0 +1,187 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync/atomic"
+)
+
+// GroupStrategy defines how the events of a consumer group are assigned to its members.
+type GroupStrategy int
+
+const (
+	// GroupRoundRobin hands the events to the members in turn. This is the default strategy.
+	GroupRoundRobin GroupStrategy = iota
+
+	// GroupLeastLoaded hands every event to the member with the fewest queued events.
+	GroupLeastLoaded
+
+	// GroupKeyHash hands the events with the same key to the same member, see WithGroupKey.
+	// Keys are assigned with rendezvous hashing, so members joining or leaving the group
+	// only move the keys they gain or lose.
+	GroupKeyHash
+)
+
+// String returns a human-readable name of the GroupStrategy.
+func (s GroupStrategy) String() string {
+	switch s {
+	case GroupRoundRobin:
+		return "round-robin"
+	case GroupLeastLoaded:
+		return "least-loaded"
+	case GroupKeyHash:
+		return "key-hash"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrGroupStrategyMismatch is returned when subscribing to a consumer group with a
+// strategy other than the one of its members.
+var ErrGroupStrategyMismatch = errors.New("consumer group uses a different strategy")
+
+// WithGroup makes the subscriber a member of the named consumer group.
+//
+// The members of a group share the events routed to the group: every event is queued
+// for a single member, chosen by the strategy among the members interested in its type.
+// Subscribers outside the group, and other groups, still receive every event. Events
+// are reassigned as members subscribe and unsubscribe.
+//
+// History replayed with FromOffset or FromBeginning isn't shared, every member replays it.
+//
+// Example:
+//
+//	// Three instances share the orders, each order being processed once.
+//	for i := 1; i <= 3; i++ {
+//	    sirkeji.Subscribe(streamer, NewOrderHandler(fmt.Sprintf("order-handler-%d", i)),
+//	        sirkeji.WithSubscribeOptions(sirkeji.WithGroup("order-handlers", sirkeji.GroupLeastLoaded)))
+//	}
+func WithGroup(name string, strategy GroupStrategy) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.group = name
+		cfg.strategy = strategy
+	}
+}
+
+// WithGroupKey sets the key the GroupKeyHash strategy assigns events by.
+// Without it, events are keyed by their Publisher. The key of the first member
+// applies to the whole group.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("account-worker-1",
+//	    WithGroup("account-workers", GroupKeyHash),
+//	    WithGroupKey(func(event Event) string { return event.Header("account-id") }))
+func WithGroupKey(key KeyFunc) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.groupKey = key
+	}
+}
+
+// consumerGroup holds the members of a consumer group.
+type consumerGroup struct {
+	name     string
+	strategy GroupStrategy
+	// key extracts the keys of the GroupKeyHash strategy, it is set by the first member.
+	key KeyFunc
+	// members holds the subscriptions of the group by UID.
+	members map[string]*subscription
+	// next is the turn of the GroupRoundRobin strategy, it breaks ties of GroupLeastLoaded.
+	next atomic.Uint64
+}
+
+// join adds the subscription to its consumer group. The caller must hold the lock.
+func (s *DefaultStreamer) join(sub *subscription) error {
+	group, exists := s.groups[sub.group]
+	if !exists {
+		group = &consumerGroup{name: sub.group, strategy: sub.strategy, key: sub.groupKey, members: make(map[string]*subscription)}
+		s.groups[sub.group] = group
+	}
+	if group.strategy != sub.strategy {
+		return fmt.Errorf("%w: %s is %s", ErrGroupStrategyMismatch, group.name, group.strategy)
+	}
+	group.members[sub.uid] = sub
+	return nil
+}
+
+// leave removes the subscription from its consumer group. The caller must hold the lock.
+func (s *DefaultStreamer) leave(sub *subscription) {
+	group, exists := s.groups[sub.group]
+	if !exists {
+		return
+	}
+	delete(group.members, sub.uid)
+	if len(group.members) == 0 {
+		delete(s.groups, sub.group)
+	}
+}
+
+// balance keeps, for every consumer group, the single routed member the event is
+// assigned to. The caller must hold the lock.
+func (s *DefaultStreamer) balance(event Event, subs []*subscription) []*subscription {
+	if len(s.groups) == 0 {
+		return subs
+	}
+
+	var candidates map[string][]*subscription
+	balanced := subs[:0]
+	for _, sub := range subs {
+		if sub.group == "" {
+			balanced = append(balanced, sub)
+			continue
+		}
+		if candidates == nil {
+			candidates = make(map[string][]*subscription)
+		}
+		candidates[sub.group] = append(candidates[sub.group], sub)
+	}
+	for name, members := range candidates {
+		balanced = append(balanced, s.groups[name].assign(event, members))
+	}
+	return balanced
+}
+
+// assign chooses the member the event is queued for among the candidates.
+func (g *consumerGroup) assign(event Event, candidates []*subscription) *subscription {
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].uid < candidates[j].uid
+	})
+
+	switch g.strategy {
+	case GroupLeastLoaded:
+		start := int(g.next.Add(1) % uint64(len(candidates)))
+		chosen := candidates[start]
+		for i := 1; i < len(candidates); i++ {
+			if sub := candidates[(start+i)%len(candidates)]; len(sub.ch) < len(chosen.ch) {
+				chosen = sub
+			}
+		}
+		return chosen
+	case GroupKeyHash:
+		key := event.Publisher
+		if g.key != nil {
+			key = g.key(event)
+		}
+		var (
+			chosen *subscription
+			best   uint64
+		)
+		for _, sub := range candidates {
+			h := fnv.New64a()
+			_, _ = h.Write([]byte(key))
+			_, _ = h.Write([]byte{0})
+			_, _ = h.Write([]byte(sub.uid))
+			if score := h.Sum64(); chosen == nil || score > best {
+				chosen, best = sub, score
+			}
+		}
+		return chosen
+	default:
+		return candidates[(g.next.Add(1)-1)%uint64(len(candidates))]
+	}
+}
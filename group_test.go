@@ -0,0 +1,166 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// subscribeGroup subscribes the given UIDs with the options and returns their channels.
+func subscribeGroup(t *testing.T, streamer *DefaultStreamer, uids []string, opts ...SubscribeOption) map[string]chan Event {
+	t.Helper()
+
+	channels := make(map[string]chan Event, len(uids))
+	for _, uid := range uids {
+		ch, err := streamer.Subscribe(uid, opts...)
+		if err != nil {
+			t.Fatalf("Failed to subscribe %s: %v", uid, err)
+		}
+		channels[uid] = ch
+	}
+	return channels
+}
+
+// TestGroupRoundRobin ensures group members share the events in turn, while other
+// subscribers and groups receive every event.
+func TestGroupRoundRobin(t *testing.T) {
+	streamer := NewStreamer()
+	workers := subscribeGroup(t, streamer, []string{"worker-1", "worker-2", "worker-3"}, WithGroup("workers", GroupRoundRobin))
+	auditors := subscribeGroup(t, streamer, []string{"auditor-1", "auditor-2"}, WithGroup("auditors", GroupRoundRobin))
+	outsider := subscribeGroup(t, streamer, []string{"outsider"})
+
+	for i := 0; i < 9; i++ {
+		streamer.Publish(InfoEvent("test", fmt.Sprint(i)))
+	}
+
+	for uid, ch := range workers {
+		if len(ch) != 3 {
+			t.Errorf("Expected %s to receive 3 events, got %d", uid, len(ch))
+		}
+	}
+	if total := len(auditors["auditor-1"]) + len(auditors["auditor-2"]); total != 9 {
+		t.Errorf("Expected the auditors to share 9 events, got %d", total)
+	}
+	if len(outsider["outsider"]) != 9 {
+		t.Errorf("Expected the outsider to receive every event, got %d", len(outsider["outsider"]))
+	}
+}
+
+// TestGroupLeastLoaded ensures events are assigned to the member with the shortest queue.
+func TestGroupLeastLoaded(t *testing.T) {
+	streamer := NewStreamer()
+	members := subscribeGroup(t, streamer, []string{"a", "b"}, WithGroup("balanced", GroupLeastLoaded), WithQueueSize(10))
+
+	for i := 0; i < 4; i++ {
+		streamer.Publish(InfoEvent("test", fmt.Sprint(i)))
+	}
+	if len(members["a"]) != 2 || len(members["b"]) != 2 {
+		t.Fatalf("Expected equally loaded members to share the events, got %d and %d", len(members["a"]), len(members["b"]))
+	}
+
+	for len(members["b"]) > 0 {
+		<-members["b"]
+	}
+	streamer.Publish(InfoEvent("test", "4"))
+	streamer.Publish(InfoEvent("test", "5"))
+
+	if len(members["a"]) != 2 || len(members["b"]) != 2 {
+		t.Errorf("Expected the drained member to receive the new events, got %d and %d", len(members["a"]), len(members["b"]))
+	}
+}
+
+// TestGroupKeyHash ensures events with the same key go to the same member, and that
+// keys only move away from members leaving the group.
+func TestGroupKeyHash(t *testing.T) {
+	streamer := NewStreamer()
+	key := WithGroupKey(func(event Event) string { return event.Meta })
+	members := subscribeGroup(t, streamer, []string{"shard-1", "shard-2", "shard-3"}, WithGroup("shards", GroupKeyHash), key, WithQueueSize(100))
+
+	assignments := func() map[string]string {
+		assigned := make(map[string]string)
+		for i := 0; i < 20; i++ {
+			streamer.Publish(InfoEvent("test", fmt.Sprintf("account-%d", i)))
+		}
+		for uid, ch := range members {
+			for len(ch) > 0 {
+				assigned[(<-ch).Meta] = uid
+			}
+		}
+		return assigned
+	}
+
+	before := assignments()
+	if again := assignments(); fmt.Sprint(again) != fmt.Sprint(before) {
+		t.Errorf("Expected stable assignments, got %v and %v", before, again)
+	}
+
+	streamer.Unsubscribe("shard-2")
+	delete(members, "shard-2")
+	after := assignments()
+
+	for account, uid := range before {
+		if uid != "shard-2" && after[account] != uid {
+			t.Errorf("Expected %s to stay on %s, moved to %s", account, uid, after[account])
+		}
+		if after[account] == "shard-2" || after[account] == "" {
+			t.Errorf("Expected %s to be reassigned, got %q", account, after[account])
+		}
+	}
+}
+
+// TestGroupRebalancing ensures events are reassigned as members join and leave.
+func TestGroupRebalancing(t *testing.T) {
+	streamer := NewStreamer()
+	members := subscribeGroup(t, streamer, []string{"first"}, WithGroup("elastic", GroupRoundRobin))
+
+	streamer.Publish(InfoEvent("test", "alone"))
+	for uid, ch := range subscribeGroup(t, streamer, []string{"second"}, WithGroup("elastic", GroupRoundRobin)) {
+		members[uid] = ch
+	}
+	streamer.Publish(InfoEvent("test", "shared-1"))
+	streamer.Publish(InfoEvent("test", "shared-2"))
+
+	if len(members["first"]) != 2 || len(members["second"]) != 1 {
+		t.Errorf("Expected the joining member to get a share, got %d and %d", len(members["first"]), len(members["second"]))
+	}
+
+	streamer.Unsubscribe("first")
+	streamer.Publish(InfoEvent("test", "left-1"))
+	streamer.Publish(InfoEvent("test", "left-2"))
+
+	if len(members["second"]) != 3 {
+		t.Errorf("Expected the remaining member to get every event, got %d", len(members["second"]))
+	}
+}
+
+// TestGroupFilteredMembers ensures events are only assigned to members interested in their type.
+func TestGroupFilteredMembers(t *testing.T) {
+	streamer := NewStreamer()
+	errorsOnly := subscribeGroup(t, streamer, []string{"errors-only"}, WithGroup("mixed", GroupRoundRobin), WithEventTypes(Error))
+	everything := subscribeGroup(t, streamer, []string{"everything"}, WithGroup("mixed", GroupRoundRobin))
+
+	for i := 0; i < 4; i++ {
+		streamer.Publish(InfoEvent("test", fmt.Sprint(i)))
+	}
+
+	if len(errorsOnly["errors-only"]) != 0 || len(everything["everything"]) != 4 {
+		t.Errorf("Expected Info events to go to the interested member only, got %d and %d",
+			len(errorsOnly["errors-only"]), len(everything["everything"]))
+	}
+}
+
+// TestGroupStrategyMismatch ensures a group can't be joined with another strategy.
+func TestGroupStrategyMismatch(t *testing.T) {
+	streamer := NewStreamer()
+	subscribeGroup(t, streamer, []string{"first"}, WithGroup("strict", GroupRoundRobin))
+
+	_, err := streamer.Subscribe("second", WithGroup("strict", GroupKeyHash))
+	if !errors.Is(err, ErrGroupStrategyMismatch) {
+		t.Fatalf("Expected ErrGroupStrategyMismatch, got %v", err)
+	}
+
+	streamer.Unsubscribe("first")
+	if _, err := streamer.Subscribe("second", WithGroup("strict", GroupKeyHash)); err != nil {
+		t.Errorf("Expected the emptied group to be recreated, got %v", err)
+	}
+}
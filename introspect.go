@@ -0,0 +1,162 @@
+package sirkeji
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SubscriberInfo describes a subscription of a DefaultStreamer, see Subscribers.
+type SubscriberInfo struct {
+	// UID is the unique identifier of the subscriber.
+	UID string `json:"uid"`
+	// SubscribedAt is the time the subscriber was subscribed.
+	SubscribedAt time.Time `json:"subscribed_at"`
+	// EventTypes holds the EventType patterns the subscriber filters on, empty for every event.
+	EventTypes []EventType `json:"event_types,omitempty"`
+	// Group is the consumer group of the subscriber, empty if it isn't in a group.
+	Group string `json:"group,omitempty"`
+	// GroupStrategy is the strategy of the consumer group, empty if it isn't in a group.
+	GroupStrategy string `json:"group_strategy,omitempty"`
+	// QueueDepth is the number of events waiting in the queue of the subscriber.
+	QueueDepth int `json:"queue_depth"`
+	// QueueCapacity is the size of the queue of the subscriber.
+	QueueCapacity int `json:"queue_capacity"`
+	// OverflowPolicy is the policy applied when the queue is full.
+	OverflowPolicy string `json:"overflow_policy"`
+	// Delivered is the number of events queued for the subscriber.
+	Delivered uint64 `json:"delivered"`
+	// LastEventAt is the time the last event was queued for the subscriber, zero if none was.
+	LastEventAt time.Time `json:"last_event_at"`
+	// ProcessingMode is the ProcessingMode of the SubscriptionManager of the subscriber,
+	// empty if the subscriber isn't managed by a SubscriptionManager.
+	ProcessingMode string `json:"processing_mode,omitempty"`
+	// Workers is the worker count of the pooled and keyed processing modes.
+	Workers int `json:"workers,omitempty"`
+}
+
+// Subscribers returns a snapshot of the subscriptions of the streamer, in subscription order.
+//
+// Returns:
+//   - A SubscriberInfo for every subscriber, including those subscribed without a
+//     SubscriptionManager, which have no ProcessingMode.
+//
+// Example:
+//
+//	for _, info := range streamer.Subscribers() {
+//	    log.Printf("%s: %d/%d queued, %d delivered", info.UID, info.QueueDepth, info.QueueCapacity, info.Delivered)
+//	}
+func (s *DefaultStreamer) Subscribers() []SubscriberInfo {
+	s.RLock()
+	infos := make([]SubscriberInfo, 0, len(s.subscribers))
+	seqs := make(map[string]uint64, len(s.subscribers))
+	for uid, sub := range s.subscribers {
+		info := SubscriberInfo{
+			UID:            uid,
+			SubscribedAt:   sub.subscribedAt,
+			EventTypes:     append([]EventType(nil), sub.eventTypes...),
+			Group:          sub.group,
+			QueueDepth:     len(sub.ch),
+			QueueCapacity:  cap(sub.ch),
+			OverflowPolicy: sub.policy.String(),
+			Delivered:      sub.delivered.Load(),
+		}
+		if sub.group != "" {
+			info.GroupStrategy = sub.strategy.String()
+		}
+		if last := sub.lastDelivery.Load(); last != 0 {
+			info.LastEventAt = time.Unix(0, last)
+		}
+		if sm := sub.manager; sm != nil {
+			info.ProcessingMode = sm.mode.String()
+			info.Workers = sm.workers
+		}
+		infos = append(infos, info)
+		seqs[uid] = sub.seq
+	}
+	s.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return seqs[infos[i].UID] < seqs[infos[j].UID]
+	})
+	return infos
+}
+
+// debugPage is the data rendered by the HTML page of NewDebugHandler.
+type debugPage struct {
+	Now         time.Time
+	Subscribers []SubscriberInfo
+}
+
+var debugTemplate = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"since": func(now, t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return now.Sub(t).Truncate(time.Millisecond).String() + " ago"
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>sirkeji subscribers</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>sirkeji subscribers</h1>
+<p>{{len .Subscribers}} subscribers at {{.Now.Format "2006-01-02 15:04:05.000 MST"}} &middot; <a href="?format=json">json</a></p>
+<table>
+<tr><th>UID</th><th>Subscribed</th><th>Event types</th><th>Group</th><th>Queue</th><th>Overflow</th><th>Delivered</th><th>Last event</th><th>Processing</th></tr>
+{{- range .Subscribers}}
+<tr>
+<td>{{.UID}}</td>
+<td>{{since $.Now .SubscribedAt}}</td>
+<td>{{if .EventTypes}}{{range $i, $t := .EventTypes}}{{if $i}}, {{end}}{{$t}}{{end}}{{else}}all{{end}}</td>
+<td>{{if .Group}}{{.Group}} ({{.GroupStrategy}}){{else}}-{{end}}</td>
+<td class="num">{{.QueueDepth}}/{{.QueueCapacity}}</td>
+<td>{{.OverflowPolicy}}</td>
+<td class="num">{{.Delivered}}</td>
+<td>{{since $.Now .LastEventAt}}</td>
+<td>{{if .ProcessingMode}}{{.ProcessingMode}}{{if .Workers}} ({{.Workers}} workers){{end}}{{else}}unmanaged{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// NewDebugHandler returns an http.Handler serving the subscriptions of the streamer,
+// as an HTML page by default and as JSON when requested with ?format=json or an
+// Accept header preferring application/json.
+//
+// Like net/http/pprof, it is meant to be mounted on an internal debug endpoint.
+//
+// Example:
+//
+//	http.Handle("/debug/sirkeji", sirkeji.NewDebugHandler(streamer))
+//	// curl 'localhost:6060/debug/sirkeji?format=json'
+func NewDebugHandler(streamer *DefaultStreamer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subscribers := streamer.Subscribers()
+
+		if r.URL.Query().Get("format") == "json" || strings.HasPrefix(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(subscribers)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := debugTemplate.Execute(w, debugPage{Now: time.Now(), Subscribers: subscribers}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
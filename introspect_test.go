@@ -0,0 +1,100 @@
+package sirkeji
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSubscribers ensures the snapshot describes every subscription.
+func TestSubscribers(t *testing.T) {
+	streamer := NewStreamer()
+	before := time.Now()
+	_, _ = streamer.Subscribe("auditor", WithEventTypes(Error, "orders.>"), WithQueueSize(4), WithOverflowPolicy(OverflowDropOldest))
+	_, _ = streamer.Subscribe("worker-1", WithGroup("workers", GroupLeastLoaded))
+	subscribeWith(t, streamer, NewMockSubscriber("pooled"), WithWorkerPool(3))
+
+	streamer.Publish(ErrorEvent("test", "first"))
+	streamer.Publish(ErrorEvent("test", "second"))
+	streamer.Publish(InfoEvent("test", "ignored by the auditor"))
+
+	infos := streamer.Subscribers()
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 subscribers, got %+v", infos)
+	}
+
+	auditor := infos[0]
+	if auditor.UID != "auditor" || auditor.SubscribedAt.Before(before) {
+		t.Errorf("expected the auditor first, subscribed after %v, got %+v", before, auditor)
+	}
+	if !reflect.DeepEqual(auditor.EventTypes, []EventType{Error, "orders.>"}) {
+		t.Errorf("expected the auditor's filters, got %v", auditor.EventTypes)
+	}
+	if auditor.QueueDepth != 2 || auditor.QueueCapacity != 4 || auditor.Delivered != 2 || auditor.OverflowPolicy != OverflowDropOldest.String() {
+		t.Errorf("expected 2 of 4 queued events, got %+v", auditor)
+	}
+	if auditor.LastEventAt.Before(auditor.SubscribedAt) {
+		t.Errorf("expected the last event time to be set, got %v", auditor.LastEventAt)
+	}
+	if auditor.ProcessingMode != "" {
+		t.Errorf("expected the auditor to be unmanaged, got %s", auditor.ProcessingMode)
+	}
+
+	if worker := infos[1]; worker.Group != "workers" || worker.GroupStrategy != "least-loaded" || worker.Delivered != 3 {
+		t.Errorf("expected the worker to get every event of its group, got %+v", worker)
+	}
+	if pooled := infos[2]; pooled.ProcessingMode != "pooled" || pooled.Workers != 3 || pooled.Delivered != 3 {
+		t.Errorf("expected the pooled subscriber's processing mode, got %+v", pooled)
+	}
+
+	streamer.Unsubscribe("auditor")
+	if infos := streamer.Subscribers(); len(infos) != 2 || infos[0].UID != "worker-1" {
+		t.Errorf("expected the auditor to be gone, got %+v", infos)
+	}
+}
+
+// TestDebugHandler ensures the subscriptions are served as JSON and HTML.
+func TestDebugHandler(t *testing.T) {
+	streamer := NewStreamer()
+	_, _ = streamer.Subscribe("<script>alert(1)</script>", WithEventTypes(Error))
+	handler := NewDebugHandler(streamer)
+
+	tests := []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{"query", "/debug/sirkeji?format=json", ""},
+		{"accept header", "/debug/sirkeji", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			var infos []SubscriberInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+				t.Fatalf("invalid JSON %s: %v", rec.Body, err)
+			}
+			if rec.Header().Get("Content-Type") != "application/json" || len(infos) != 1 || infos[0].EventTypes[0] != Error {
+				t.Errorf("expected the subscriber as JSON, got %s", rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/sirkeji", nil))
+	body := rec.Body.String()
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || !strings.Contains(body, "1 subscribers") {
+		t.Errorf("expected an HTML page listing the subscriber, got %s", body)
+	}
+	if strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Errorf("expected the UID to be escaped, got %s", body)
+	}
+}
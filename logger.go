@@ -1,27 +1,186 @@
 package sirkeji
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"sort"
+	"sync"
 	"time"
 )
 
-// Logger is a Subscriber implementation that logs all received events.
+// LogFormat selects how the Logger formats its records.
+type LogFormat int
+
+const (
+	// LogFormatText writes records as logfmt-style key=value pairs. This is the default format.
+	LogFormatText LogFormat = iota
+
+	// LogFormatJSON writes records as JSON objects, one per line.
+	LogFormatJSON
+)
+
+// Logger is a Subscriber implementation that logs the received events as structured
+// log/slog records.
+//
+// Every record carries the Meta of the event as its message, and the event's ID, Type,
+// Publisher, CorrelationID, CausationID, Offset, Headers and Payload as attributes.
+// Error and DeadLetter events are logged at the Error level, Shutdown events at the
+// Warn level and every other event at the Info level, see WithEventLevel.
 //
 // Logs are written to the screen by default (os.Stdout). Additional outputs
 // such as a bytes.Buffer or a file can be added dynamically.
 type Logger struct {
-	uid              string
-	baseOutput       io.Writer
-	additionalOutput io.Writer
-	logger           *log.Logger
+	uid        string
+	baseOutput io.Writer
+	file       *RotatingFile
+	output     *logOutput
+	format     LogFormat
+	minLevel   slog.Level
+	eventTypes []EventType
+	levels     map[EventType]slog.Level
+	codec      Codec
+	handler    slog.Handler
+	logger     *slog.Logger
+}
+
+// LoggerOption configures a Logger.
+type LoggerOption func(*Logger)
+
+// WithLogFormat selects the format of the records, LogFormatText by default.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogFormat(LogFormatJSON))
+func WithLogFormat(format LogFormat) LoggerOption {
+	return func(l *Logger) {
+		l.format = format
+	}
+}
+
+// WithLogLevel discards the events logged below the given level, slog.LevelInfo by default.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogLevel(slog.LevelWarn)) // only Shutdown and Error events
+func WithLogLevel(level slog.Level) LoggerOption {
+	return func(l *Logger) {
+		l.minLevel = level
+	}
+}
+
+// WithEventLevel logs the events of the given EventType at the given level.
+//
+// Example:
+//
+//	logger := NewLogger(WithEventLevel(Reply, slog.LevelDebug))
+func WithEventLevel(eventType EventType, level slog.Level) LoggerOption {
+	return func(l *Logger) {
+		l.levels[eventType] = level
+	}
+}
+
+// WithLogEventTypes restricts the Logger to events of the given types.
+//
+// Types may be hierarchical patterns with wildcards, see MatchEventType. The Logger
+// declares them through EventTypes, so the Streamer doesn't route other events to it.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogEventTypes(Error, "orders.>"))
+func WithLogEventTypes(eventTypes ...EventType) LoggerOption {
+	return func(l *Logger) {
+		l.eventTypes = append(l.eventTypes, eventTypes...)
+	}
+}
+
+// WithLogPayloadCodec renders every payload with the given Codec.
+//
+// By default payloads are rendered with the Codec of their EventType, and formatted
+// with fmt otherwise. Payloads encoded with JSONCodec are embedded as JSON, payloads
+// encoded with other codecs are rendered as base64.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogFormat(LogFormatJSON), WithLogPayloadCodec(JSONCodec))
+func WithLogPayloadCodec(codec Codec) LoggerOption {
+	return func(l *Logger) {
+		l.codec = codec
+	}
+}
+
+// WithLogOutput replaces the screen (os.Stdout) as the base output of the Logger.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogOutput(os.Stderr))
+func WithLogOutput(output io.Writer) LoggerOption {
+	return func(l *Logger) {
+		l.baseOutput = output
+	}
+}
+
+// WithLogFile writes the records to a RotatingFile at the given path as well.
+//
+// Combine it with WithLogOutput(io.Discard) to write to the file only.
+//
+// Parameters:
+//   - path: The path of the log file.
+//   - opts: Optional RotateOptions configuring when the file is rotated.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogFormat(LogFormatJSON),
+//	    WithLogFile("/var/log/app/events.log", WithMaxSize(50<<20), WithMaxArchives(10), WithCompression()))
+//	defer logger.Close()
+func WithLogFile(path string, opts ...RotateOption) LoggerOption {
+	return func(l *Logger) {
+		l.file = NewRotatingFile(path, opts...)
+	}
+}
+
+// WithLogHandler writes the records to the given slog.Handler instead of the Logger's
+// outputs. WithLogFormat, WithLogOutput, WithLogFile and SetAdditionalOutput have no effect then.
+//
+// Example:
+//
+//	logger := NewLogger(WithLogHandler(slog.Default().Handler()))
+func WithLogHandler(handler slog.Handler) LoggerOption {
+	return func(l *Logger) {
+		l.handler = handler
+	}
+}
+
+// logOutput is an io.Writer whose destination can be replaced while the Logger is in use.
+type logOutput struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func (o *logOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.w.Write(p)
+}
+
+func (o *logOutput) set(w io.Writer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.w = w
 }
 
 // NewLogger creates a new Logger instance.
 //
-// Logs are written to the screen (os.Stdout) by default.
+// Logs are written to the screen (os.Stdout) in the text format by default.
+//
+// Parameters:
+//   - opts: Optional LoggerOptions, e.g. WithLogFormat, WithLogLevel or WithLogEventTypes.
 //
 // Returns:
 //   - A pointer to a new Logger instance.
@@ -29,12 +188,38 @@ type Logger struct {
 // Example:
 //
 //	logger := NewLogger()
-func NewLogger() *Logger {
-	return &Logger{
+//	jsonLogger := NewLogger(WithLogFormat(LogFormatJSON), WithLogLevel(slog.LevelWarn))
+func NewLogger(opts ...LoggerOption) *Logger {
+	l := &Logger{
 		uid:        fmt.Sprintf("logger-%d", time.Now().UnixMilli()),
 		baseOutput: os.Stdout,
-		logger:     log.New(os.Stdout, "", log.LstdFlags|log.Lmicroseconds),
+		minLevel:   slog.LevelInfo,
+		levels: map[EventType]slog.Level{
+			Error:      slog.LevelError,
+			DeadLetter: slog.LevelError,
+			Shutdown:   slog.LevelWarn,
+		},
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+
+	if l.file != nil {
+		l.baseOutput = io.MultiWriter(l.baseOutput, l.file)
+	}
+
+	handler := l.handler
+	if handler == nil {
+		l.output = &logOutput{w: l.baseOutput}
+		handlerOpts := &slog.HandlerOptions{Level: l.minLevel}
+		if l.format == LogFormatJSON {
+			handler = slog.NewJSONHandler(l.output, handlerOpts)
+		} else {
+			handler = slog.NewTextHandler(l.output, handlerOpts)
+		}
+	}
+	l.logger = slog.New(handler)
+	return l
 }
 
 // SetAdditionalOutput sets an optional second output for the logger.
@@ -43,8 +228,9 @@ func NewLogger() *Logger {
 //   - output: An io.Writer (e.g., bytes.Buffer, file) to receive log messages in addition to the screen.
 //
 // Behavior:
-//   - Updates the logger to write to both os.Stdout and the provided output.
-//   - If no additional output is set, the logger writes only to os.Stdout.
+//   - Updates the logger to write to both its base output and the provided output.
+//   - If no additional output is set, the logger writes only to its base output.
+//   - Has no effect on Loggers created with WithLogHandler.
 //
 // Example:
 //
@@ -55,12 +241,13 @@ func NewLogger() *Logger {
 //	defer file.Close()
 //	logger.SetAdditionalOutput(file)
 func (l *Logger) SetAdditionalOutput(output io.Writer) {
+	if l.output == nil {
+		return
+	}
 	if output != nil {
-		l.additionalOutput = output
-		multiWriter := io.MultiWriter(l.baseOutput, l.additionalOutput)
-		l.logger.SetOutput(multiWriter)
+		l.output.set(io.MultiWriter(l.baseOutput, output))
 	} else {
-		l.logger.SetOutput(l.baseOutput)
+		l.output.set(l.baseOutput)
 	}
 }
 
@@ -78,25 +265,106 @@ func (l *Logger) Uid() string {
 	return l.uid
 }
 
+// EventTypes returns the EventTypes configured with WithLogEventTypes.
+//
+// Returns:
+//   - nil if the Logger logs every event.
+func (l *Logger) EventTypes() []EventType {
+	return l.eventTypes
+}
+
+// Level returns the level the events of the given EventType are logged at.
+func (l *Logger) Level(eventType EventType) slog.Level {
+	if level, ok := l.levels[eventType]; ok {
+		return level
+	}
+	return slog.LevelInfo
+}
+
 // Process logs the details of the received event.
 //
 // Parameters:
 //   - event: The Event to be logged.
 //
 // Behavior:
-//   - Logs the Publisher, Type, Meta, and Payload status of the event.
-//   - Indicates whether the Payload is "empty" or "full".
+//   - Logs the event at the level of its EventType, if enabled.
+//   - Logs the Meta as the message, and the identifiers, Publisher, Type, Headers
+//     and rendered Payload of the event as attributes.
 //
 // Example:
 //
 //	event := Event{Publisher: "system", Type: "Info", Meta: "App started", Payload: nil}
 //	logger.Process(event)
 func (l *Logger) Process(event Event) {
-	payloadStatus := "empty"
+	ctx := context.Background()
+	level := l.Level(event.Type)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("id", event.ID),
+		slog.String("type", string(event.Type)),
+		slog.String("publisher", event.Publisher),
+	}
+	if event.CorrelationID != "" {
+		attrs = append(attrs, slog.String("correlation_id", event.CorrelationID))
+	}
+	if event.CausationID != "" && event.CausationID != event.ID {
+		attrs = append(attrs, slog.String("causation_id", event.CausationID))
+	}
+	if event.Offset != 0 {
+		attrs = append(attrs, slog.Uint64("offset", event.Offset))
+	}
+	if len(event.Headers) > 0 {
+		keys := make([]string, 0, len(event.Headers))
+		for key := range event.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		headers := make([]any, 0, len(keys))
+		for _, key := range keys {
+			headers = append(headers, slog.String(key, event.Headers[key]))
+		}
+		attrs = append(attrs, slog.Group("headers", headers...))
+	}
 	if event.Payload != nil {
-		payloadStatus = "full"
+		attrs = append(attrs, l.payloadAttr(event))
+	}
+
+	l.logger.LogAttrs(ctx, level, event.Meta, attrs...)
+}
+
+// payloadAttr renders the payload of the event, see WithLogPayloadCodec.
+func (l *Logger) payloadAttr(event Event) slog.Attr {
+	codec := l.codec
+	if codec == nil {
+		info, _ := lookupEventType(event.Type)
+		codec = info.codec
+	}
+	if codec == nil {
+		return slog.String("payload", fmt.Sprintf("%+v", event.Payload))
+	}
+
+	data, err := codec.Marshal(event.Payload)
+	if err != nil {
+		return slog.String("payload_error", err.Error())
+	}
+	if codec.Name() == JSONCodec.Name() {
+		return slog.Any("payload", json.RawMessage(data))
+	}
+	return slog.String("payload", base64.StdEncoding.EncodeToString(data))
+}
+
+// Close closes the log file configured with WithLogFile, if any.
+//
+// The file is reopened if the Logger keeps receiving events.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
 	}
-	l.logger.Printf("[%s] *%s*, m: %s | pl: %s", event.Publisher, event.Type, event.Meta, payloadStatus)
+	return l.file.Close()
 }
 
 // OnSubscribed is called when the Logger is successfully subscribed to a Streamer.
@@ -0,0 +1,198 @@
+package sirkeji
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// TestLoggerJSONRecord ensures events are logged as structured JSON records.
+func TestLoggerJSONRecord(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogFormat(LogFormatJSON), WithLogOutput(&buf))
+
+	event := stampEvent(NewEvent("orders", codecTestJSONOrder.Type(), "order placed", codecTestOrder{ID: "order-7", Items: []string{"book"}})).
+		WithHeader("tenant", "acme")
+	logger.Process(event)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]interface{}{
+		"level":          "INFO",
+		"msg":            "order placed",
+		"id":             event.ID,
+		"type":           string(event.Type),
+		"publisher":      "orders",
+		"correlation_id": event.CorrelationID,
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+
+	headers, _ := record["headers"].(map[string]interface{})
+	if headers["tenant"] != "acme" {
+		t.Errorf("Expected the headers to be logged, got %v", record["headers"])
+	}
+	payload, _ := record["payload"].(map[string]interface{})
+	if payload["ID"] != "order-7" {
+		t.Errorf("Expected the payload to be embedded as JSON, got %v", record["payload"])
+	}
+}
+
+// TestLoggerLevels ensures EventTypes are mapped to levels and filtered by the minimum level.
+func TestLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf), WithLogLevel(slog.LevelWarn))
+
+	logger.Process(InfoEvent("app", "started"))
+	logger.Process(NewEvent("main", Shutdown, "stopping", nil))
+	logger.Process(ErrorEvent("app", "failed"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "level=WARN") || !strings.Contains(lines[0], "msg=stopping") {
+		t.Errorf("Expected the Shutdown event at the Warn level, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=ERROR") || !strings.Contains(lines[1], "msg=failed") {
+		t.Errorf("Expected the Error event at the Error level, got %q", lines[1])
+	}
+}
+
+// TestLoggerEventLevel ensures WithEventLevel overrides the level of an EventType.
+func TestLoggerEventLevel(t *testing.T) {
+	logger := NewLogger(WithEventLevel(Info, slog.LevelDebug))
+
+	if level := logger.Level(Info); level != slog.LevelDebug {
+		t.Errorf("Expected Info events at the Debug level, got %v", level)
+	}
+	if level := logger.Level(Error); level != slog.LevelError {
+		t.Errorf("Expected Error events at the Error level, got %v", level)
+	}
+	if level := logger.Level("Unknown"); level != slog.LevelInfo {
+		t.Errorf("Expected other events at the Info level, got %v", level)
+	}
+}
+
+// TestLoggerPayloadRendering ensures payloads are rendered with the configured codecs.
+func TestLoggerPayloadRendering(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []LoggerOption
+		event    Event
+		expected string
+	}{
+		{
+			name:     "without codec",
+			event:    NewEvent("test", codecTestNoCodec, "", 42),
+			expected: "payload=42",
+		},
+		{
+			name:     "event type codec",
+			event:    NewEvent("test", codecTestUntyped, "", map[string]int{"n": 1}),
+			expected: `payload="{\"n\":1}"`,
+		},
+		{
+			name:     "logger codec",
+			opts:     []LoggerOption{WithLogPayloadCodec(JSONCodec)},
+			event:    NewEvent("test", codecTestNoCodec, "", []int{1, 2}),
+			expected: `payload="[1,2]"`,
+		},
+		{
+			name:     "binary codec",
+			event:    NewEvent("test", codecTestGobOrder.Type(), "", &codecTestOrder{ID: "order-1"}),
+			expected: "payload=",
+		},
+		{
+			name:     "encoding failure",
+			opts:     []LoggerOption{WithLogPayloadCodec(JSONCodec)},
+			event:    NewEvent("test", codecTestNoCodec, "", make(chan int)),
+			expected: "payload_error=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(append(tt.opts, WithLogOutput(&buf))...)
+			logger.Process(tt.event)
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected the record to contain %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+// TestLoggerSetAdditionalOutput ensures records are written to the additional output as well.
+func TestLoggerSetAdditionalOutput(t *testing.T) {
+	var base, additional bytes.Buffer
+	logger := NewLogger(WithLogOutput(&base))
+
+	logger.SetAdditionalOutput(&additional)
+	logger.Process(InfoEvent("app", "first"))
+
+	logger.SetAdditionalOutput(nil)
+	logger.Process(InfoEvent("app", "second"))
+
+	if strings.Count(base.String(), "\n") != 2 {
+		t.Errorf("Expected 2 records in the base output, got %q", base.String())
+	}
+	if !strings.Contains(additional.String(), "msg=first") || strings.Contains(additional.String(), "msg=second") {
+		t.Errorf("Expected only the first record in the additional output, got %q", additional.String())
+	}
+}
+
+// TestLoggerEventTypes ensures the Logger is only routed the configured EventTypes.
+func TestLoggerEventTypes(t *testing.T) {
+	var buf lockedBuffer
+	logger := NewLogger(WithLogOutput(&buf), WithLogEventTypes(Error))
+
+	streamer := NewStreamer()
+	manager, err := NewSubscriptionManager(streamer, logger)
+	if err != nil {
+		t.Fatalf("Failed to create subscription manager: %v", err)
+	}
+	if err := manager.Subscribe(); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer manager.Unsubscribe()
+
+	streamer.Publish(InfoEvent("app", "ignored"))
+	streamer.Publish(ErrorEvent("app", "logged"))
+	time.Sleep(50 * time.Millisecond)
+
+	if strings.Contains(buf.String(), "ignored") || !strings.Contains(buf.String(), "logged") {
+		t.Errorf("Expected only the Error event to be logged, got %q", buf.String())
+	}
+}
@@ -0,0 +1,372 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from a DefaultStreamer and its SubscriptionManagers.
+//
+// Implementations must be safe for concurrent use and must not block, they are called
+// from the publishing and processing goroutines. MetricsRegistry is the included
+// implementation, other implementations may forward the measurements to any metrics library.
+type Metrics interface {
+	// EventPublished is called for every event published on the Streamer.
+	EventPublished(eventType EventType)
+
+	// EventDelivered is called for every event queued for a subscriber.
+	EventDelivered(subscriberUid string)
+
+	// EventDropped is called for every event dropped or rejected by a subscriber's queue.
+	EventDropped(subscriberUid string, eventType EventType, err error)
+
+	// QueueDepth is called with the number of events waiting in a subscriber's queue.
+	QueueDepth(subscriberUid string, depth int)
+
+	// EventProcessed is called with the time a subscriber spent processing an event.
+	EventProcessed(subscriberUid string, eventType EventType, d time.Duration)
+
+	// ProcessPanicked is called for every panic recovered from a subscriber.
+	ProcessPanicked(subscriberUid string)
+
+	// SubscriberRemoved is called once a subscriber is unsubscribed, so its measurements
+	// can be forgotten. A SubscriptionManager calls it again once it processed the events
+	// left in the queue.
+	SubscriberRemoved(subscriberUid string)
+}
+
+// WithMetrics reports the measurements of the DefaultStreamer to the given Metrics.
+//
+// SubscriptionManagers subscribing to the streamer report their processing
+// measurements to the same Metrics, unless configured with WithProcessMetrics.
+//
+// Example:
+//
+//	metrics := NewMetricsRegistry()
+//	streamer := NewStreamer(WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+func WithMetrics(metrics Metrics) StreamerOption {
+	return func(s *DefaultStreamer) {
+		s.metrics = metrics
+	}
+}
+
+// WithProcessMetrics reports the processing measurements of the SubscriptionManager to
+// the given Metrics.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithProcessMetrics(metrics))
+func WithProcessMetrics(metrics Metrics) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.metrics = metrics
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the processing latency histogram
+// of the MetricsRegistries created afterwards.
+var DefaultLatencyBuckets = []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricKind is the type of a metric family in the text exposition format.
+type metricKind string
+
+const (
+	counterMetric   metricKind = "counter"
+	gaugeMetric     metricKind = "gauge"
+	histogramMetric metricKind = "histogram"
+)
+
+// metricFamily holds the series of a metric, by label values.
+type metricFamily struct {
+	name   string
+	help   string
+	kind   metricKind
+	labels []string
+	// bounds are the bucket upper bounds of histograms.
+	bounds []float64
+	series map[string]*metricSeries
+}
+
+// metricSeries is a single labelled series of a metric family.
+type metricSeries struct {
+	labelValues []string
+	// value is the value of counters and gauges.
+	value float64
+	// buckets, sum and count are the state of histograms.
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// MetricsRegistry is a dependency-free Metrics implementation, exposing the measurements
+// in the Prometheus text exposition format.
+//
+// Exposed metrics:
+//   - sirkeji_events_published_total{type}: Counter of published events.
+//   - sirkeji_events_delivered_total{subscriber}: Counter of events queued for subscribers.
+//   - sirkeji_events_dropped_total{subscriber,type,reason}: Counter of dropped or rejected events.
+//   - sirkeji_queue_depth{subscriber}: Gauge of the events waiting in subscriber queues.
+//   - sirkeji_processing_duration_seconds{subscriber,type}: Histogram of processing latencies.
+//   - sirkeji_panics_total{subscriber}: Counter of panics recovered from subscribers.
+//
+// The series of a subscriber are deleted once it is unsubscribed, so short-lived subscribers,
+// like the clients of a WebSocketGateway, don't pile up.
+//
+// It implements http.Handler, serving the metrics to Prometheus scrapers.
+type MetricsRegistry struct {
+	published *metricFamily
+	delivered *metricFamily
+	dropped   *metricFamily
+	depth     *metricFamily
+	latency   *metricFamily
+	panics    *metricFamily
+
+	// families lists the metric families in exposition order.
+	families []*metricFamily
+	mu       sync.Mutex
+}
+
+// NewMetricsRegistry creates an empty MetricsRegistry.
+//
+// Example:
+//
+//	metrics := NewMetricsRegistry()
+//	streamer := NewStreamer(WithMetrics(metrics))
+//	go http.ListenAndServe(":9090", metrics)
+func NewMetricsRegistry() *MetricsRegistry {
+	r := &MetricsRegistry{
+		published: newMetricFamily("sirkeji_events_published_total", "Events published, by type.", counterMetric, "type"),
+		delivered: newMetricFamily("sirkeji_events_delivered_total", "Events queued for subscribers.", counterMetric, "subscriber"),
+		dropped:   newMetricFamily("sirkeji_events_dropped_total", "Events dropped or rejected by subscriber queues.", counterMetric, "subscriber", "type", "reason"),
+		depth:     newMetricFamily("sirkeji_queue_depth", "Events waiting in subscriber queues.", gaugeMetric, "subscriber"),
+		latency:   newMetricFamily("sirkeji_processing_duration_seconds", "Time subscribers spent processing events.", histogramMetric, "subscriber", "type"),
+		panics:    newMetricFamily("sirkeji_panics_total", "Panics recovered from subscribers.", counterMetric, "subscriber"),
+	}
+	r.families = []*metricFamily{r.published, r.delivered, r.dropped, r.depth, r.latency, r.panics}
+	return r
+}
+
+func newMetricFamily(name, help string, kind metricKind, labels ...string) *metricFamily {
+	f := &metricFamily{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*metricSeries)}
+	if kind == histogramMetric {
+		f.bounds = slices.Clone(DefaultLatencyBuckets)
+	}
+	return f
+}
+
+// get returns the series with the given label values, creating it if needed.
+// The caller must hold the registry lock.
+func (f *metricFamily) get(labelValues ...string) *metricSeries {
+	key := strings.Join(labelValues, "\xff")
+	series, ok := f.series[key]
+	if !ok {
+		series = &metricSeries{labelValues: labelValues}
+		if f.kind == histogramMetric {
+			series.buckets = make([]uint64, len(f.bounds))
+		}
+		f.series[key] = series
+	}
+	return series
+}
+
+// EventPublished implements Metrics.
+func (r *MetricsRegistry) EventPublished(eventType EventType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.published.get(string(eventType)).value++
+}
+
+// EventDelivered implements Metrics.
+func (r *MetricsRegistry) EventDelivered(subscriberUid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.delivered.get(subscriberUid).value++
+}
+
+// EventDropped implements Metrics.
+func (r *MetricsRegistry) EventDropped(subscriberUid string, eventType EventType, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropped.get(subscriberUid, string(eventType), dropReason(err)).value++
+}
+
+// dropReason returns a short label value describing why an event was dropped.
+func dropReason(err error) string {
+	switch {
+	case errors.Is(err, ErrEventDropped):
+		return "dropped"
+	case errors.Is(err, ErrQueueFull):
+		return "queue_full"
+	case errors.Is(err, ErrDeliveryTimeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+// QueueDepth implements Metrics.
+func (r *MetricsRegistry) QueueDepth(subscriberUid string, depth int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.depth.get(subscriberUid).value = float64(depth)
+}
+
+// EventProcessed implements Metrics.
+func (r *MetricsRegistry) EventProcessed(subscriberUid string, eventType EventType, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	series := r.latency.get(subscriberUid, string(eventType))
+	seconds := d.Seconds()
+	for i, bound := range r.latency.bounds {
+		if seconds <= bound {
+			series.buckets[i]++
+		}
+	}
+	series.sum += seconds
+	series.count++
+}
+
+// ProcessPanicked implements Metrics.
+func (r *MetricsRegistry) ProcessPanicked(subscriberUid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.panics.get(subscriberUid).value++
+}
+
+// SubscriberRemoved implements Metrics, deleting the series of the subscriber.
+//
+// The series of subscribers subscribing again with the same UID start over.
+func (r *MetricsRegistry) SubscriberRemoved(subscriberUid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.families {
+		i := slices.Index(f.labels, "subscriber")
+		if i < 0 {
+			continue
+		}
+		for key, series := range f.series {
+			if series.labelValues[i] == subscriberUid {
+				delete(f.series, key)
+			}
+		}
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+//
+// Returns:
+//   - The number of bytes written and the first write error, if any.
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	r.mu.Lock()
+	for _, f := range r.families {
+		f.write(&b)
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = r.WriteTo(w)
+}
+
+// write formats the family, its series sorted by label values. The caller must hold the registry lock.
+func (f *metricFamily) write(b *strings.Builder) {
+	if len(f.series) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		series := f.series[key]
+		labels := formatLabels(f.labels, series.labelValues)
+		if f.kind != histogramMetric {
+			fmt.Fprintf(b, "%s%s %s\n", f.name, labels, formatValue(series.value))
+			continue
+		}
+
+		bucket := func(le string) string {
+			return formatLabels(append(slices.Clone(f.labels), "le"), append(slices.Clone(series.labelValues), le))
+		}
+		for i, bound := range f.bounds {
+			fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, bucket(formatValue(bound)), series.buckets[i])
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, bucket("+Inf"), series.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", f.name, labels, formatValue(series.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", f.name, labels, series.count)
+	}
+}
+
+// formatLabels formats a label set, escaping the values.
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes label values as required by the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatValue formats a sample value.
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// resolveMetrics returns the Metrics of the manager, falling back to the ones of the streamer.
+func (sm *SubscriptionManager) resolveMetrics() Metrics {
+	if sm.metrics != nil {
+		return sm.metrics
+	}
+	if s, ok := sm.streamer.(interface{ processMetrics() Metrics }); ok {
+		return s.processMetrics()
+	}
+	return nil
+}
+
+// measure wraps process, reporting the time spent processing every event.
+func (sm *SubscriptionManager) measure(metrics Metrics, process ProcessFunc) ProcessFunc {
+	uid := sm.subscriber.Uid()
+	return func(event Event) {
+		start := sm.clock.Now()
+		defer func() {
+			metrics.EventProcessed(uid, event.Type, sm.clock.Since(start))
+		}()
+		process(event)
+	}
+}
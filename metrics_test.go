@@ -0,0 +1,131 @@
+package sirkeji
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMetricsRegistry ensures streamer and manager measurements are exposed in the text format.
+func TestMetricsRegistry(t *testing.T) {
+	metrics := NewMetricsRegistry()
+	streamer := NewStreamer(WithMetrics(metrics))
+
+	panicked := make(chan struct{}, 1)
+	subscribeWith(t, streamer, &PanickingSubscriber{MockSubscriber: NewMockSubscriber("panicking"), panicOn: Info},
+		WithPanicHandler(func(p *PanicError) { panicked <- struct{}{} }))
+	_, _ = streamer.Subscribe("full", WithQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+
+	streamer.Publish(InfoEvent("system", "first"))
+	streamer.Publish(InfoEvent("system", "second"))
+
+	select {
+	case <-panicked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscriber to panic")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("expected the text exposition content type, got %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE sirkeji_events_published_total counter\n",
+		`sirkeji_events_published_total{type="Info"} 2` + "\n",
+		`sirkeji_events_delivered_total{subscriber="full"} 1` + "\n",
+		`sirkeji_events_delivered_total{subscriber="panicking"} 2` + "\n",
+		`sirkeji_events_dropped_total{subscriber="full",type="Info",reason="dropped"} 1` + "\n",
+		`sirkeji_queue_depth{subscriber="full"} 1` + "\n",
+		"# TYPE sirkeji_processing_duration_seconds histogram\n",
+		`sirkeji_processing_duration_seconds_bucket{subscriber="panicking",type="Info",le="+Inf"} 2` + "\n",
+		`sirkeji_processing_duration_seconds_count{subscriber="panicking",type="Info"} 2` + "\n",
+		`sirkeji_panics_total{subscriber="panicking"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// TestMetricsExposition ensures label values are escaped and histogram buckets are cumulative.
+func TestMetricsExposition(t *testing.T) {
+	metrics := NewMetricsRegistry()
+	metrics.EventDropped("quote\"d\\sub\nscriber", "orders.created", errors.New("boom"))
+	metrics.EventProcessed("worker", "orders.created", 3*time.Millisecond)
+	metrics.EventProcessed("worker", "orders.created", 2*time.Second)
+
+	var b strings.Builder
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := b.String()
+
+	for _, want := range []string{
+		`sirkeji_events_dropped_total{subscriber="quote\"d\\sub\nscriber",type="orders.created",reason="error"} 1`,
+		`sirkeji_processing_duration_seconds_bucket{subscriber="worker",type="orders.created",le="0.001"} 0`,
+		`sirkeji_processing_duration_seconds_bucket{subscriber="worker",type="orders.created",le="0.005"} 1`,
+		`sirkeji_processing_duration_seconds_bucket{subscriber="worker",type="orders.created",le="2.5"} 2`,
+		`sirkeji_processing_duration_seconds_sum{subscriber="worker",type="orders.created"} 2.003`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "sirkeji_panics_total") {
+		t.Error("expected metrics without series to be omitted")
+	}
+}
+
+// TestMetricsSubscriberRemoved ensures the series of unsubscribed subscribers are deleted.
+func TestMetricsSubscriberRemoved(t *testing.T) {
+	metrics := NewMetricsRegistry()
+	streamer := NewStreamer(WithMetrics(metrics))
+
+	manager, err := NewSubscriptionManager(streamer, NewMockSubscriber("managed"), WithSequentialProcessing())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.Subscribe(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = streamer.Subscribe("unmanaged", WithQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+	_, _ = streamer.Subscribe("remaining")
+
+	streamer.Publish(InfoEvent("system", "first"))
+	streamer.Publish(InfoEvent("system", "second"))
+	manager.Unsubscribe()
+	streamer.Unsubscribe("unmanaged")
+
+	expose := func() string {
+		var b strings.Builder
+		_, _ = metrics.WriteTo(&b)
+		return b.String()
+	}
+	deadline := time.Now().Add(time.Second)
+	for strings.Contains(expose(), `subscriber="managed"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the managed subscriber's series to be deleted, got:\n%s", expose())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	body := expose()
+	if strings.Contains(body, `subscriber="unmanaged"`) {
+		t.Errorf("expected the unmanaged subscriber's series to be deleted, got:\n%s", body)
+	}
+	for _, want := range []string{
+		`sirkeji_events_published_total{type="Info"} 2` + "\n",
+		`sirkeji_events_delivered_total{subscriber="remaining"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package sirkeji
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// PublishFunc publishes an event, it is the unit wrapped by PublishMiddleware.
+type PublishFunc func(ctx context.Context, event Event) error
+
+// PublishMiddleware wraps the publishing of events on a DefaultStreamer.
+//
+// A middleware may inspect or modify the event before calling next, skip next to
+// reject the event, or act on the returned error.
+type PublishMiddleware func(next PublishFunc) PublishFunc
+
+// ProcessFunc processes an event, it is the unit wrapped by ProcessMiddleware.
+type ProcessFunc func(event Event)
+
+// ProcessMiddleware wraps the Process method of a Subscriber managed by a SubscriptionManager.
+type ProcessMiddleware func(next ProcessFunc) ProcessFunc
+
+// WithPublishMiddleware adds middlewares around every Publish and PublishContext call.
+//
+// Middlewares run in the order given: the first one sees the event first and the
+// returned error last. Events reach middlewares already stamped with an ID and time.
+//
+// Example:
+//
+//	streamer := NewStreamer(WithPublishMiddleware(
+//	    ValidateEventType(),
+//	    InjectHeaders(map[string]string{"service": "billing"}),
+//	))
+func WithPublishMiddleware(middlewares ...PublishMiddleware) StreamerOption {
+	return func(s *DefaultStreamer) {
+		s.publishMiddlewares = append(s.publishMiddlewares, middlewares...)
+	}
+}
+
+// WithProcessMiddleware adds middlewares around the Subscriber's Process method.
+//
+// Middlewares run in the order given: the first one sees the event first. Panics
+// raised by middlewares are recovered like panics raised by Process.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithProcessMiddleware(
+//	    TimeProcess(func(event Event, d time.Duration) {
+//	        log.Printf("processed %s in %v", event.Type, d)
+//	    }),
+//	))
+func WithProcessMiddleware(middlewares ...ProcessMiddleware) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.processMiddlewares = append(sm.processMiddlewares, middlewares...)
+	}
+}
+
+// chainPublish wraps publish with the middlewares, the first middleware being outermost.
+func chainPublish(publish PublishFunc, middlewares []PublishMiddleware) PublishFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		publish = middlewares[i](publish)
+	}
+	return publish
+}
+
+// chainProcess wraps process with the middlewares, the first middleware being outermost.
+func chainProcess(process ProcessFunc, middlewares []ProcessMiddleware) ProcessFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		process = middlewares[i](process)
+	}
+	return process
+}
+
+// ValidateEventType rejects events whose EventType isn't registered, or whose payload
+// doesn't match the payload type bound to their EventType.
+//
+// Returns:
+//   - A PublishMiddleware failing with ErrUnregisteredEventType or a *PayloadTypeError.
+//
+// Example:
+//
+//	streamer := NewStreamer(WithPublishMiddleware(ValidateEventType()))
+func ValidateEventType() PublishMiddleware {
+	return func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, event Event) error {
+			info, exists := lookupEventType(event.Type)
+			if !exists {
+				return fmt.Errorf("%w: %s", ErrUnregisteredEventType, event.Type)
+			}
+			if info.payloadType != nil && event.Payload != nil && reflect.TypeOf(event.Payload) != info.payloadType {
+				return &PayloadTypeError{
+					EventType: event.Type,
+					Event:     event,
+					Expected:  info.payloadType.String(),
+					Actual:    reflect.TypeOf(event.Payload).String(),
+				}
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// InjectHeaders sets the given headers on every published event.
+//
+// Headers already set on an event are left untouched.
+//
+// Example:
+//
+//	streamer := NewStreamer(WithPublishMiddleware(InjectHeaders(map[string]string{
+//	    "host": hostname,
+//	})))
+func InjectHeaders(headers map[string]string) PublishMiddleware {
+	return func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, event Event) error {
+			for key, value := range headers {
+				if _, ok := event.Headers[key]; !ok {
+					event = event.WithHeader(key, value)
+				}
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// TimePublish reports how long publishing each event took, including the time spent
+// waiting for subscribers.
+//
+// Example:
+//
+//	streamer := NewStreamer(WithPublishMiddleware(TimePublish(func(event Event, d time.Duration, err error) {
+//	    publishLatency.Observe(d.Seconds())
+//	})))
+func TimePublish(observe func(event Event, d time.Duration, err error)) PublishMiddleware {
+	return func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next(ctx, event)
+			observe(event, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// TimeProcess reports how long processing each event took.
+//
+// Example:
+//
+//	sirkeji.Subscribe(streamer, subscriber, sirkeji.WithProcessMiddleware(
+//	    sirkeji.TimeProcess(func(event sirkeji.Event, d time.Duration) {
+//	        log.Printf("processed %s in %v", event.Type, d)
+//	    })))
+func TimeProcess(observe func(event Event, d time.Duration)) ProcessMiddleware {
+	return func(next ProcessFunc) ProcessFunc {
+		return func(event Event) {
+			start := time.Now()
+			defer func() {
+				observe(event, time.Since(start))
+			}()
+			next(event)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingPublishMiddleware appends its name to calls before and after calling next.
+func recordingPublishMiddleware(name string, calls *[]string) PublishMiddleware {
+	return func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, event Event) error {
+			*calls = append(*calls, name+":before")
+			err := next(ctx, event)
+			*calls = append(*calls, name+":after")
+			return err
+		}
+	}
+}
+
+// TestPublishMiddlewareOrder ensures publish middlewares run in the order they are given.
+func TestPublishMiddlewareOrder(t *testing.T) {
+	var calls []string
+	streamer := NewStreamer(WithPublishMiddleware(
+		recordingPublishMiddleware("first", &calls),
+		recordingPublishMiddleware("second", &calls),
+	))
+
+	streamer.Publish(InfoEvent("system", "hello"))
+
+	expected := []string{"first:before", "second:before", "second:after", "first:after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+// TestValidateEventType ensures events not matching the registry are rejected.
+func TestValidateEventType(t *testing.T) {
+	streamer := NewStreamer(WithPublishMiddleware(ValidateEventType()))
+	ch, _ := streamer.Subscribe("user123")
+
+	t.Run("Registered type", func(t *testing.T) {
+		if err := streamer.PublishContext(context.Background(), InfoEvent("system", "hello")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ch) != 1 {
+			t.Fatalf("expected the event to be delivered, got %d events", len(ch))
+		}
+		<-ch
+	})
+
+	t.Run("Unregistered type", func(t *testing.T) {
+		err := streamer.PublishContext(context.Background(), NewEvent("system", "MiddlewareUnknown", "", nil))
+		if !errors.Is(err, ErrUnregisteredEventType) {
+			t.Fatalf("expected ErrUnregisteredEventType, got %v", err)
+		}
+		if len(ch) != 0 {
+			t.Fatal("expected the event not to be delivered")
+		}
+	})
+
+	t.Run("Mismatching payload", func(t *testing.T) {
+		err := streamer.PublishContext(context.Background(), NewEvent("system", testNumberEvent.Type(), "", "seven"))
+		var typeErr *PayloadTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected a *PayloadTypeError, got %v", err)
+		}
+		if len(ch) != 0 {
+			t.Fatal("expected the event not to be delivered")
+		}
+	})
+}
+
+// TestInjectHeaders ensures headers are injected without overriding existing ones.
+func TestInjectHeaders(t *testing.T) {
+	streamer := NewStreamer(WithPublishMiddleware(InjectHeaders(map[string]string{
+		"service": "billing",
+		"region":  "eu",
+	})))
+	ch, _ := streamer.Subscribe("user123")
+
+	streamer.Publish(InfoEvent("system", "hello").WithHeader("region", "us"))
+
+	event := <-ch
+	if event.Header("service") != "billing" {
+		t.Errorf("expected header 'service' to be injected, got %v", event.Headers)
+	}
+	if event.Header("region") != "us" {
+		t.Errorf("expected header 'region' to be kept, got %v", event.Headers)
+	}
+}
+
+// TestProcessMiddleware ensures process middlewares wrap Process in order and are timed.
+func TestProcessMiddleware(t *testing.T) {
+	var (
+		calls []string
+		mu    sync.Mutex
+	)
+	record := func(name string) ProcessMiddleware {
+		return func(next ProcessFunc) ProcessFunc {
+			return func(event Event) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				next(event)
+			}
+		}
+	}
+	timed := make(chan time.Duration, 1)
+
+	streamer := NewStreamer()
+	subscriber := NewMockSubscriber("middleware-subscriber")
+	subscribeWith(t, streamer, subscriber, WithProcessMiddleware(
+		record("first"),
+		record("second"),
+		TimeProcess(func(event Event, d time.Duration) { timed <- d }),
+	))
+
+	streamer.Publish(InfoEvent("system", "hello"))
+
+	select {
+	case <-timed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the processing time to be observed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, []string{"first", "second"}) {
+		t.Errorf("expected calls [first second], got %v", calls)
+	}
+	if len(subscriber.GetProcessedEvents()) != 1 {
+		t.Errorf("expected the subscriber to process the event")
+	}
+}
@@ -0,0 +1,217 @@
+package sirkeji
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// ProcessingMode defines how a SubscriptionManager hands events over to its Subscriber.
+type ProcessingMode int
+
+const (
+	// ProcessConcurrent calls Process in a new goroutine for every event.
+	// It offers no ordering guarantee and is the default mode.
+	ProcessConcurrent ProcessingMode = iota
+
+	// ProcessSequential calls Process for one event at a time, in the order events were published.
+	ProcessSequential
+
+	// ProcessPooled calls Process from a fixed number of worker goroutines.
+	ProcessPooled
+
+	// ProcessKeyed calls Process from a fixed number of worker goroutines, while events
+	// sharing the same key are always processed by the same worker, in order.
+	ProcessKeyed
+)
+
+// String returns a human-readable name of the ProcessingMode.
+func (m ProcessingMode) String() string {
+	switch m {
+	case ProcessConcurrent:
+		return "concurrent"
+	case ProcessSequential:
+		return "sequential"
+	case ProcessPooled:
+		return "pooled"
+	case ProcessKeyed:
+		return "keyed"
+	default:
+		return "unknown"
+	}
+}
+
+// KeyFunc extracts the ordering key of an event for ProcessKeyed processing.
+type KeyFunc func(event Event) string
+
+// WithSequentialProcessing makes the SubscriptionManager process events one at a time,
+// in the order they were received from the Streamer.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithSequentialProcessing())
+func WithSequentialProcessing() ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.mode = ProcessSequential
+	}
+}
+
+// WithWorkerPool makes the SubscriptionManager process events using a fixed number of workers.
+//
+// Parameters:
+//   - size: The number of workers. Values below 1 are treated as 1.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithWorkerPool(8))
+func WithWorkerPool(size int) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.mode = ProcessPooled
+		sm.workers = size
+	}
+}
+
+// WithKeyedWorkerPool makes the SubscriptionManager process events using a fixed number of
+// workers, keeping events with the same key in order.
+//
+// Parameters:
+//   - size: The number of workers. Values below 1 are treated as 1.
+//   - key: Extracts the ordering key of an event. If nil, events are keyed by their Publisher.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber,
+//	    WithKeyedWorkerPool(8, func(event Event) string { return event.Meta }))
+func WithKeyedWorkerPool(size int, key KeyFunc) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.mode = ProcessKeyed
+		sm.workers = size
+		sm.keyFunc = key
+	}
+}
+
+// dispatcher hands events over to a process function according to a ProcessingMode.
+type dispatcher interface {
+	// dispatch schedules the event for processing, it may block while workers are busy.
+	dispatch(event Event)
+	// stop waits until every dispatched event has been processed.
+	stop()
+}
+
+// newDispatcher creates the dispatcher for the given mode.
+func newDispatcher(mode ProcessingMode, workers int, key KeyFunc, process ProcessFunc) dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+
+	switch mode {
+	case ProcessSequential:
+		return sequentialDispatcher(process)
+	case ProcessPooled:
+		return newPoolDispatcher(workers, process)
+	case ProcessKeyed:
+		if key == nil {
+			key = func(event Event) string { return event.Publisher }
+		}
+		return newKeyedDispatcher(workers, key, process)
+	default:
+		return &concurrentDispatcher{process: process}
+	}
+}
+
+// concurrentDispatcher processes every event in its own goroutine.
+type concurrentDispatcher struct {
+	process ProcessFunc
+	wg      sync.WaitGroup
+}
+
+func (d *concurrentDispatcher) dispatch(event Event) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.process(event)
+	}()
+}
+
+func (d *concurrentDispatcher) stop() {
+	d.wg.Wait()
+}
+
+// sequentialDispatcher processes events inline, in the receiving goroutine.
+type sequentialDispatcher ProcessFunc
+
+func (d sequentialDispatcher) dispatch(event Event) {
+	d(event)
+}
+
+func (d sequentialDispatcher) stop() {}
+
+// poolDispatcher processes events from a shared queue using a fixed number of workers.
+type poolDispatcher struct {
+	queue chan Event
+	wg    sync.WaitGroup
+}
+
+func newPoolDispatcher(workers int, process ProcessFunc) *poolDispatcher {
+	d := &poolDispatcher{queue: make(chan Event)}
+
+	d.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer d.wg.Done()
+			for event := range d.queue {
+				process(event)
+			}
+		}()
+	}
+	return d
+}
+
+func (d *poolDispatcher) dispatch(event Event) {
+	d.queue <- event
+}
+
+func (d *poolDispatcher) stop() {
+	close(d.queue)
+	d.wg.Wait()
+}
+
+// keyedDispatcher routes events to a worker chosen by the hash of their key,
+// so events sharing a key are processed sequentially.
+type keyedDispatcher struct {
+	key    KeyFunc
+	queues []chan Event
+	wg     sync.WaitGroup
+}
+
+func newKeyedDispatcher(workers int, key KeyFunc, process ProcessFunc) *keyedDispatcher {
+	d := &keyedDispatcher{
+		key:    key,
+		queues: make([]chan Event, workers),
+	}
+
+	d.wg.Add(workers)
+	for i := range d.queues {
+		queue := make(chan Event)
+		d.queues[i] = queue
+		go func() {
+			defer d.wg.Done()
+			for event := range queue {
+				process(event)
+			}
+		}()
+	}
+	return d
+}
+
+func (d *keyedDispatcher) dispatch(event Event) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(d.key(event)))
+	d.queues[h.Sum32()%uint32(len(d.queues))] <- event
+}
+
+func (d *keyedDispatcher) stop() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
@@ -0,0 +1,142 @@
+package sirkeji
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencySubscriber records processed payloads and the peak number of concurrent Process calls.
+type concurrencySubscriber struct {
+	*MockSubscriber
+	delay   time.Duration
+	active  atomic.Int32
+	peak    atomic.Int32
+	done    sync.WaitGroup
+	perKeys map[string][]int
+}
+
+func newConcurrencySubscriber(uid string, expected int, delay time.Duration) *concurrencySubscriber {
+	cs := &concurrencySubscriber{
+		MockSubscriber: NewMockSubscriber(uid),
+		delay:          delay,
+		perKeys:        map[string][]int{},
+	}
+	cs.done.Add(expected)
+	return cs
+}
+
+func (cs *concurrencySubscriber) Process(event Event) {
+	defer cs.done.Done()
+
+	active := cs.active.Add(1)
+	for {
+		peak := cs.peak.Load()
+		if active <= peak || cs.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+	time.Sleep(cs.delay)
+	cs.active.Add(-1)
+
+	cs.Lock()
+	cs.perKeys[event.Meta] = append(cs.perKeys[event.Meta], event.Payload.(int))
+	cs.Unlock()
+	cs.MockSubscriber.Process(event)
+}
+
+func (cs *concurrencySubscriber) wait(t *testing.T) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		cs.done.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for events to be processed")
+	}
+}
+
+func subscribeWith(t *testing.T, streamer Streamer, subscriber Subscriber, opts ...ManagerOption) {
+	t.Helper()
+
+	manager, err := NewSubscriptionManager(streamer, subscriber, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.Subscribe(); err != nil {
+		t.Fatalf("unexpected error during subscription: %v", err)
+	}
+}
+
+// TestSequentialProcessing ensures events are processed one at a time and in order.
+func TestSequentialProcessing(t *testing.T) {
+	streamer := NewStreamer()
+	subscriber := newConcurrencySubscriber("sequential", 20, time.Millisecond)
+	subscribeWith(t, streamer, subscriber, WithSequentialProcessing())
+
+	for i := 0; i < 20; i++ {
+		streamer.Publish(Event{Publisher: "test", Type: Info, Payload: i})
+	}
+	subscriber.wait(t)
+
+	if peak := subscriber.peak.Load(); peak != 1 {
+		t.Errorf("expected at most 1 concurrent Process call, got %d", peak)
+	}
+	for i, event := range subscriber.GetProcessedEvents() {
+		if event.Payload != i {
+			t.Fatalf("expected payload %d at position %d, got %v", i, i, event.Payload)
+		}
+	}
+}
+
+// TestWorkerPoolProcessing ensures the number of concurrent Process calls is bounded by the pool size.
+func TestWorkerPoolProcessing(t *testing.T) {
+	streamer := NewStreamer()
+	subscriber := newConcurrencySubscriber("pooled", 40, 5*time.Millisecond)
+	subscribeWith(t, streamer, subscriber, WithWorkerPool(3))
+
+	for i := 0; i < 40; i++ {
+		streamer.Publish(Event{Publisher: "test", Type: Info, Payload: i})
+	}
+	subscriber.wait(t)
+
+	if peak := subscriber.peak.Load(); peak > 3 {
+		t.Errorf("expected at most 3 concurrent Process calls, got %d", peak)
+	}
+	if got := len(subscriber.GetProcessedEvents()); got != 40 {
+		t.Errorf("expected 40 processed events, got %d", got)
+	}
+}
+
+// TestKeyedWorkerPoolProcessing ensures events sharing a key keep their order across the pool.
+func TestKeyedWorkerPoolProcessing(t *testing.T) {
+	streamer := NewStreamer()
+	subscriber := newConcurrencySubscriber("keyed", 60, time.Millisecond)
+	subscribeWith(t, streamer, subscriber,
+		WithKeyedWorkerPool(4, func(event Event) string { return event.Meta }))
+
+	for i := 0; i < 60; i++ {
+		streamer.Publish(Event{Publisher: "test", Type: Info, Meta: "key-" + strconv.Itoa(i%5), Payload: i})
+	}
+	subscriber.wait(t)
+
+	if peak := subscriber.peak.Load(); peak > 4 {
+		t.Errorf("expected at most 4 concurrent Process calls, got %d", peak)
+	}
+
+	subscriber.Lock()
+	defer subscriber.Unlock()
+	for key, payloads := range subscriber.perKeys {
+		for i := 1; i < len(payloads); i++ {
+			if payloads[i] < payloads[i-1] {
+				t.Fatalf("events for %s processed out of order: %v", key, payloads)
+			}
+		}
+	}
+}
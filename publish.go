@@ -0,0 +1,112 @@
+package sirkeji
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PublishMode defines whether PublishContext waits for subscribers with full queues.
+type PublishMode int
+
+const (
+	// WaitForEnqueue waits until the event is queued for every subscriber, as allowed
+	// by their OverflowPolicy, or until the context is done. This is the default mode.
+	WaitForEnqueue PublishMode = iota
+
+	// FireAndForget offers the event to every subscriber without waiting. Subscribers
+	// using OverflowBlock or OverflowBlockTimeout whose queue is full are skipped and
+	// reported with ErrQueueFull.
+	FireAndForget
+)
+
+// PublishOption configures a single PublishContext call.
+type PublishOption func(*publishConfig)
+
+// publishConfig holds the settings collected from PublishOptions.
+type publishConfig struct {
+	mode PublishMode
+	// delivered receives the number of subscribers the event was queued for, if set.
+	delivered *int
+}
+
+// newPublishConfig applies the given options on top of the defaults.
+func newPublishConfig(opts []PublishOption) publishConfig {
+	cfg := publishConfig{mode: WaitForEnqueue}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithPublishMode selects between WaitForEnqueue and FireAndForget delivery.
+//
+// Example:
+//
+//	err := streamer.PublishContext(ctx, event, WithPublishMode(FireAndForget))
+func WithPublishMode(mode PublishMode) PublishOption {
+	return func(cfg *publishConfig) {
+		cfg.mode = mode
+	}
+}
+
+// withDeliveredCount stores the number of subscribers the event was queued for in
+// delivered. Streamers other than DefaultStreamer leave it untouched.
+func withDeliveredCount(delivered *int) PublishOption {
+	return func(cfg *publishConfig) {
+		cfg.delivered = delivered
+	}
+}
+
+// DeliveryError is returned by PublishContext when an event didn't reach every
+// interested subscriber.
+//
+// Fields:
+//   - Event: The Event that was published.
+//   - Failures: The reason the event wasn't queued, keyed by subscriber UID.
+//     Reasons are ErrQueueFull, ErrEventDropped, ErrDeliveryTimeout or the context's error.
+type DeliveryError struct {
+	Event    Event
+	Failures map[string]error
+}
+
+// Undelivered returns the sorted UIDs of the subscribers that didn't receive the event.
+func (e *DeliveryError) Undelivered() []string {
+	uids := make([]string, 0, len(e.Failures))
+	for uid := range e.Failures {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// Error implements the error interface.
+func (e *DeliveryError) Error() string {
+	uids := e.Undelivered()
+	reasons := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		reasons = append(reasons, fmt.Sprintf("%s (%v)", uid, e.Failures[uid]))
+	}
+	return fmt.Sprintf("%s event %s not delivered to %d subscriber(s): %s",
+		e.Event.Type, e.Event.ID, len(uids), strings.Join(reasons, ", "))
+}
+
+// Unwrap returns the distinct delivery failure reasons, so errors.Is can match
+// ErrQueueFull, context.DeadlineExceeded and the like.
+func (e *DeliveryError) Unwrap() []error {
+	var errs []error
+	for _, uid := range e.Undelivered() {
+		err := e.Failures[uid]
+		duplicate := false
+		for _, seen := range errs {
+			if seen == err {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,99 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestPublishContext ensures PublishContext reports subscribers that didn't receive the event.
+func TestPublishContext(t *testing.T) {
+	t.Run("Delivered to every subscriber", func(t *testing.T) {
+		streamer := NewStreamer()
+		ch1, _ := streamer.Subscribe("user1")
+		ch2, _ := streamer.Subscribe("user2")
+
+		if err := streamer.PublishContext(context.Background(), InfoEvent("system", "hello")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ch1) != 1 || len(ch2) != 1 {
+			t.Fatalf("expected the event to be queued for both subscribers, got %d and %d", len(ch1), len(ch2))
+		}
+	})
+
+	t.Run("Context deadline", func(t *testing.T) {
+		streamer := NewStreamer()
+		_, _ = streamer.Subscribe("stuck", WithQueueSize(0))
+		fast, _ := streamer.Subscribe("fast")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := streamer.PublishContext(ctx, InfoEvent("system", "hello"))
+
+		var deliveryErr *DeliveryError
+		if !errors.As(err, &deliveryErr) {
+			t.Fatalf("expected a *DeliveryError, got %v", err)
+		}
+		if !reflect.DeepEqual(deliveryErr.Undelivered(), []string{"stuck"}) {
+			t.Errorf("expected only 'stuck' to be undelivered, got %v", deliveryErr.Undelivered())
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the error to wrap context.DeadlineExceeded, got %v", err)
+		}
+		if len(fast) != 1 {
+			t.Errorf("expected the event to be queued for 'fast', got %d events", len(fast))
+		}
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		streamer := NewStreamer()
+		_, _ = streamer.Subscribe("user1")
+		_, _ = streamer.Subscribe("user2")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := streamer.PublishContext(ctx, InfoEvent("system", "hello"))
+
+		var deliveryErr *DeliveryError
+		if !errors.As(err, &deliveryErr) {
+			t.Fatalf("expected a *DeliveryError, got %v", err)
+		}
+		if len(deliveryErr.Undelivered()) != 2 {
+			t.Errorf("expected both subscribers to be undelivered, got %v", deliveryErr.Undelivered())
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the error to wrap context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Fire and forget", func(t *testing.T) {
+		streamer := NewStreamer()
+		_, _ = streamer.Subscribe("stuck", WithQueueSize(0))
+
+		start := time.Now()
+		err := streamer.PublishContext(context.Background(), InfoEvent("system", "hello"), WithPublishMode(FireAndForget))
+
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("expected fire and forget not to wait, took %v", elapsed)
+		}
+		if !errors.Is(err, ErrQueueFull) {
+			t.Errorf("expected the error to wrap ErrQueueFull, got %v", err)
+		}
+	})
+
+	t.Run("Dropped by overflow policy", func(t *testing.T) {
+		streamer := NewStreamer()
+		_, _ = streamer.Subscribe("dropping", WithQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+
+		_ = streamer.PublishContext(context.Background(), InfoEvent("system", "first"))
+		err := streamer.PublishContext(context.Background(), InfoEvent("system", "second"))
+
+		if !errors.Is(err, ErrEventDropped) {
+			t.Errorf("expected the error to wrap ErrEventDropped, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,522 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultQueueSize is the number of events buffered for each subscriber when no
+// explicit queue size is configured with WithQueueSize.
+const DefaultQueueSize = 64
+
+// OverflowPolicy defines how the Streamer behaves when a subscriber's queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the publisher wait until the subscriber has room for the event.
+	// This is the default policy and matches the behavior of an unbuffered channel.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards the event being published when the queue is full.
+	OverflowDropNewest
+
+	// OverflowDropOldest evicts the oldest queued event to make room for the new one.
+	OverflowDropOldest
+
+	// OverflowBlockTimeout makes the publisher wait up to the configured block timeout
+	// and discards the event if the subscriber still has no room for it.
+	OverflowBlockTimeout
+
+	// OverflowError rejects the event immediately with ErrQueueFull when the queue is full.
+	OverflowError
+)
+
+// String returns a human-readable name of the OverflowPolicy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowBlockTimeout:
+		return "block-timeout"
+	case OverflowError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// ErrQueueFull is reported when an event is rejected by a full queue using the OverflowError policy.
+	ErrQueueFull = errors.New("subscriber queue is full")
+
+	// ErrEventDropped is reported when an event is discarded by the OverflowDropNewest
+	// or OverflowDropOldest policies.
+	ErrEventDropped = errors.New("event dropped from subscriber queue")
+
+	// ErrDeliveryTimeout is reported when an event could not be queued within the block
+	// timeout of the OverflowBlockTimeout policy.
+	ErrDeliveryTimeout = errors.New("event delivery timed out")
+
+	// ErrSubscriberClosed is returned when an event is delivered to a subscriber that
+	// has already been unsubscribed.
+	ErrSubscriberClosed = errors.New("subscriber is closed")
+)
+
+// OverflowHandler is invoked by the Streamer whenever an event could not be queued
+// for a subscriber, or was evicted from its queue.
+//
+// Parameters:
+//   - subscriberUid: The unique identifier of the affected subscriber.
+//   - event: The Event that was dropped or rejected.
+//   - err: The reason, one of ErrQueueFull, ErrEventDropped or ErrDeliveryTimeout.
+type OverflowHandler func(subscriberUid string, event Event, err error)
+
+// SubscribeOption configures the queue of a single subscription.
+type SubscribeOption func(*subscribeConfig)
+
+// subscribeConfig holds the per-subscriber settings collected from SubscribeOptions.
+type subscribeConfig struct {
+	queueSize    int
+	policy       OverflowPolicy
+	blockTimeout time.Duration
+	eventTypes   []EventType
+	replayFrom   uint64
+	group        string
+	strategy     GroupStrategy
+	groupKey     KeyFunc
+}
+
+// newSubscribeConfig applies the given options on top of the defaults.
+func newSubscribeConfig(opts []SubscribeOption) subscribeConfig {
+	cfg := subscribeConfig{
+		queueSize:    DefaultQueueSize,
+		policy:       OverflowBlock,
+		blockTimeout: time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.queueSize < 0 {
+		cfg.queueSize = 0
+	}
+	// Evicting the oldest event requires at least one slot to evict from.
+	if cfg.policy == OverflowDropOldest && cfg.queueSize == 0 {
+		cfg.queueSize = 1
+	}
+	return cfg
+}
+
+// WithQueueSize sets the number of events buffered for the subscriber.
+//
+// A size of zero creates an unbuffered queue, where every event is handed over
+// directly to the subscriber.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("user123", WithQueueSize(1024))
+func WithQueueSize(size int) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.queueSize = size
+	}
+}
+
+// WithOverflowPolicy sets the policy applied when the subscriber's queue is full.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("metrics", WithOverflowPolicy(OverflowDropOldest))
+func WithOverflowPolicy(policy OverflowPolicy) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.policy = policy
+	}
+}
+
+// WithBlockTimeout selects the OverflowBlockTimeout policy with the given timeout.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("slow-consumer", WithBlockTimeout(50*time.Millisecond))
+func WithBlockTimeout(timeout time.Duration) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.policy = OverflowBlockTimeout
+		cfg.blockTimeout = timeout
+	}
+}
+
+// WithEventTypes restricts the subscription to events of the given types.
+//
+// Types may be hierarchical patterns with wildcards, e.g. "orders.*" or "orders.>",
+// see MatchEventType. The Streamer routes events by type, so subscribers are never
+// handed events they didn't declare. Calling it without any types leaves the
+// subscription unfiltered.
+//
+// Example:
+//
+//	ch, err := streamer.Subscribe("auditor", WithEventTypes(Error, Shutdown))
+//	ch, err := streamer.Subscribe("order-history", WithEventTypes("orders.>"))
+func WithEventTypes(eventTypes ...EventType) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.eventTypes = append(cfg.eventTypes, eventTypes...)
+	}
+}
+
+// subscription is the Streamer side of a single subscriber: its bounded queue and
+// the overflow policy used when publishing into it.
+type subscription struct {
+	uid string
+	ch  chan Event
+	subscribeConfig
+
+	// onOverflow reports dropped and rejected events, it may be nil.
+	onOverflow OverflowHandler
+
+	// done is closed when the subscription is being closed, releasing blocked publishers.
+	done chan struct{}
+	// mu guards closed, publishers hold the read lock while sending on ch.
+	mu     sync.RWMutex
+	closed bool
+
+	// replaying is set while history is replayed from the EventLog, live events
+	// are held in pending meanwhile and queued once the replay is over. room is
+	// closed when the replay takes the pending events, releasing blocked publishers.
+	replaying atomic.Bool
+	replayMu  sync.Mutex
+	pending   []Event
+	room      chan struct{}
+
+	// subscribedAt is the time the subscription was created.
+	subscribedAt time.Time
+	// delivered counts the events queued for the subscriber.
+	delivered atomic.Uint64
+	// lastDelivery is the time, in Unix nanoseconds, the last event was queued.
+	lastDelivery atomic.Int64
+
+	// seq orders the subscriptions of a Streamer by subscription time.
+	seq uint64
+	// manager is the SubscriptionManager consuming the queue, if any, see GracefulShutdown.
+	// It is guarded by the lock of the Streamer.
+	manager *SubscriptionManager
+	// unprocessed counts the events queued or held for the subscriber that weren't
+	// processed yet. Events are counted before they are queued, so an event taken off
+	// the queue is always accounted for until its SubscriptionManager is done with it.
+	unprocessed atomic.Int64
+}
+
+// newSubscription creates a subscription with a queue sized according to cfg.
+func newSubscription(uid string, cfg subscribeConfig, onOverflow OverflowHandler) *subscription {
+	return &subscription{
+		uid:             uid,
+		ch:              make(chan Event, cfg.queueSize),
+		subscribeConfig: cfg,
+		onOverflow:      onOverflow,
+		done:            make(chan struct{}),
+		subscribedAt:    time.Now(),
+	}
+}
+
+// queued records an event queued for the subscriber.
+func (s *subscription) queued() {
+	s.delivered.Add(1)
+	s.lastDelivery.Store(time.Now().UnixNano())
+}
+
+// deliver queues the event according to the subscription's overflow policy.
+//
+// Parameters:
+//   - ctx: Bounds the time spent waiting for room in the queue.
+//   - event: The Event to queue.
+//   - wait: Whether the blocking policies may wait for room; if false they behave like OverflowError.
+//
+// Returns:
+//   - nil if the event was queued.
+//   - ErrSubscriberClosed if the subscription is closed.
+//   - The context's error if it was done before the event could be queued.
+//   - ErrEventDropped, ErrQueueFull or ErrDeliveryTimeout if the event was not queued.
+func (s *subscription) deliver(ctx context.Context, event Event, wait bool) (err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrSubscriberClosed
+	}
+	s.unprocessed.Add(1)
+	defer func() {
+		if err != nil {
+			s.unprocessed.Add(-1)
+		}
+	}()
+	policy := s.policy
+	if !wait && s.blocking() {
+		policy = OverflowError
+	}
+
+	var timeout <-chan time.Time
+	if policy == OverflowBlockTimeout {
+		timer := time.NewTimer(s.blockTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		held, room := s.hold(event, policy == OverflowDropOldest)
+		if held {
+			return nil
+		}
+		if room == nil {
+			break
+		}
+
+		// The events held during the replay fill the queue, wait for the replay to take them.
+		switch policy {
+		case OverflowDropNewest:
+			err = ErrEventDropped
+		case OverflowError:
+			err = ErrQueueFull
+		default:
+			select {
+			case <-room:
+				continue
+			case <-s.done:
+				return ErrSubscriberClosed
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timeout:
+				err = ErrDeliveryTimeout
+			}
+		}
+		s.overflow(event, err)
+		return err
+	}
+
+	switch policy {
+	case OverflowDropNewest, OverflowError:
+		select {
+		case s.ch <- event:
+			s.queued()
+			return nil
+		default:
+			err = ErrEventDropped
+			if policy == OverflowError {
+				err = ErrQueueFull
+			}
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.ch <- event:
+				s.queued()
+				return nil
+			default:
+			}
+			select {
+			case evicted := <-s.ch:
+				s.unprocessed.Add(-1)
+				s.overflow(evicted, ErrEventDropped)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- event:
+			s.queued()
+			return nil
+		case <-s.done:
+			return ErrSubscriberClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			err = ErrDeliveryTimeout
+		}
+	}
+
+	s.overflow(event, err)
+	return err
+}
+
+// blocking reports whether the subscription's policy may make publishers wait.
+func (s *subscription) blocking() bool {
+	return s.policy == OverflowBlock || s.policy == OverflowBlockTimeout
+}
+
+// offer queues the event only if there is room for it right away.
+//
+// Unlike deliver, it doesn't report the event as overflown when the queue is full.
+//
+// Returns:
+//   - true if the event was queued.
+func (s *subscription) offer(event Event) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+	s.unprocessed.Add(1)
+	if held, room := s.hold(event, false); held {
+		return true
+	} else if room != nil {
+		s.unprocessed.Add(-1)
+		return false
+	}
+
+	select {
+	case s.ch <- event:
+		s.queued()
+		return true
+	default:
+		s.unprocessed.Add(-1)
+		return false
+	}
+}
+
+// accepts reports whether the subscription is interested in the given EventType.
+//
+// The Streamer routes events with its topicTrie, accepts is used for replayed history.
+func (s *subscription) accepts(eventType EventType) bool {
+	if len(s.eventTypes) == 0 {
+		return true
+	}
+	for _, pattern := range s.eventTypes {
+		if MatchEventType(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// hold keeps the event in pending while the subscription is replaying history.
+//
+// Like the queue, pending holds up to queueSize events, at least one. When it is full
+// the oldest held event is evicted if evict is set, otherwise the event isn't held.
+//
+// Returns:
+//   - true if the event was held.
+//   - A channel closed once the replay takes the held events if pending is full,
+//     nil if the subscription isn't replaying or the event was held.
+func (s *subscription) hold(event Event, evict bool) (bool, <-chan struct{}) {
+	if !s.replaying.Load() {
+		return false, nil
+	}
+
+	s.replayMu.Lock()
+	if !s.replaying.Load() {
+		s.replayMu.Unlock()
+		return false, nil
+	}
+
+	var evicted []Event
+	if len(s.pending) >= max(s.queueSize, 1) {
+		if !evict {
+			if s.room == nil {
+				s.room = make(chan struct{})
+			}
+			room := s.room
+			s.replayMu.Unlock()
+			return false, room
+		}
+		evicted, s.pending = s.pending[:1:1], s.pending[1:]
+	}
+	s.pending = append(s.pending, event)
+	s.replayMu.Unlock()
+
+	for _, e := range evicted {
+		s.unprocessed.Add(-1)
+		s.overflow(e, ErrEventDropped)
+	}
+	return true, nil
+}
+
+// send queues the event, waiting for room regardless of the overflow policy.
+func (s *subscription) send(event Event) error {
+	s.unprocessed.Add(1)
+	if err := s.push(event); err != nil {
+		s.unprocessed.Add(-1)
+		return err
+	}
+	return nil
+}
+
+// push queues an event already counted as unprocessed, waiting for room regardless
+// of the overflow policy.
+func (s *subscription) push(event Event) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrSubscriberClosed
+	}
+	select {
+	case s.ch <- event:
+		s.queued()
+		return nil
+	case <-s.done:
+		return ErrSubscriberClosed
+	}
+}
+
+// replay queues the logged events in the offset range [from, to), followed by the
+// live events held meanwhile. The subscription must have replaying set.
+func (s *subscription) replay(eventLog *EventLog, from, to uint64) {
+	err := eventLog.replay(from, to, func(event Event) error {
+		if !s.accepts(event.Type) {
+			return nil
+		}
+		return s.send(event)
+	})
+	if err != nil && !errors.Is(err, ErrSubscriberClosed) {
+		log.Printf("[%s] replay from offset %d stopped: %v\n", s.uid, from, err)
+	}
+
+	for {
+		s.replayMu.Lock()
+		pending := s.pending
+		s.pending = nil
+		if len(pending) == 0 {
+			s.replaying.Store(false)
+		}
+		if s.room != nil {
+			close(s.room)
+			s.room = nil
+		}
+		s.replayMu.Unlock()
+
+		if len(pending) == 0 {
+			return
+		}
+		// Held events were counted as unprocessed when they were published, the
+		// ones that can't be queued anymore are uncounted.
+		for i, event := range pending {
+			if s.push(event) != nil {
+				s.unprocessed.Add(-int64(len(pending) - i))
+				break
+			}
+		}
+	}
+}
+
+// overflow reports an event that didn't make it to the subscriber.
+func (s *subscription) overflow(event Event, err error) {
+	if s.onOverflow != nil {
+		s.onOverflow(s.uid, event, err)
+	}
+}
+
+// close releases blocked publishers and closes the event channel.
+// It must be called at most once.
+func (s *subscription) close() {
+	close(s.done)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	close(s.ch)
+}
@@ -0,0 +1,259 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// overflowRecorder collects the events reported to an OverflowHandler.
+type overflowRecorder struct {
+	events []Event
+	errs   []error
+	sync.Mutex
+}
+
+func (r *overflowRecorder) handle(_ string, event Event, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.events = append(r.events, event)
+	r.errs = append(r.errs, err)
+}
+
+func (r *overflowRecorder) get() ([]Event, []error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.events, r.errs
+}
+
+func numberedEvent(n int) Event {
+	return Event{Publisher: "test", Type: Info, Payload: n}
+}
+
+// TestOverflowPolicies ensures each overflow policy handles a full queue as documented.
+func TestOverflowPolicies(t *testing.T) {
+	t.Run("Drop newest", func(t *testing.T) {
+		recorder := &overflowRecorder{}
+		streamer := NewStreamer(WithOverflowHandler(recorder.handle))
+		ch, _ := streamer.Subscribe("slow", WithQueueSize(2), WithOverflowPolicy(OverflowDropNewest))
+
+		for i := 0; i < 3; i++ {
+			streamer.Publish(numberedEvent(i))
+		}
+
+		if got := (<-ch).Payload; got != 0 {
+			t.Errorf("expected first queued payload 0, got %v", got)
+		}
+		if got := (<-ch).Payload; got != 1 {
+			t.Errorf("expected second queued payload 1, got %v", got)
+		}
+		events, errs := recorder.get()
+		if len(events) != 1 || events[0].Payload != 2 || !errors.Is(errs[0], ErrEventDropped) {
+			t.Fatalf("expected payload 2 to be dropped, got %v %v", events, errs)
+		}
+	})
+
+	t.Run("Drop oldest", func(t *testing.T) {
+		recorder := &overflowRecorder{}
+		streamer := NewStreamer(WithOverflowHandler(recorder.handle))
+		ch, _ := streamer.Subscribe("slow", WithQueueSize(2), WithOverflowPolicy(OverflowDropOldest))
+
+		for i := 0; i < 3; i++ {
+			streamer.Publish(numberedEvent(i))
+		}
+
+		if got := (<-ch).Payload; got != 1 {
+			t.Errorf("expected first queued payload 1, got %v", got)
+		}
+		if got := (<-ch).Payload; got != 2 {
+			t.Errorf("expected second queued payload 2, got %v", got)
+		}
+		events, errs := recorder.get()
+		if len(events) != 1 || events[0].Payload != 0 || !errors.Is(errs[0], ErrEventDropped) {
+			t.Fatalf("expected payload 0 to be evicted, got %v %v", events, errs)
+		}
+	})
+
+	t.Run("Block with timeout", func(t *testing.T) {
+		recorder := &overflowRecorder{}
+		streamer := NewStreamer(WithOverflowHandler(recorder.handle))
+		_, _ = streamer.Subscribe("slow", WithQueueSize(1), WithBlockTimeout(20*time.Millisecond))
+
+		start := time.Now()
+		streamer.Publish(numberedEvent(0))
+		streamer.Publish(numberedEvent(1))
+
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("expected publish to block for the timeout, returned after %v", elapsed)
+		}
+		_, errs := recorder.get()
+		if len(errs) != 1 || !errors.Is(errs[0], ErrDeliveryTimeout) {
+			t.Fatalf("expected a single ErrDeliveryTimeout, got %v", errs)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		recorder := &overflowRecorder{}
+		streamer := NewStreamer(WithOverflowHandler(recorder.handle))
+		_, _ = streamer.Subscribe("slow", WithQueueSize(1), WithOverflowPolicy(OverflowError))
+
+		streamer.Publish(numberedEvent(0))
+		streamer.Publish(numberedEvent(1))
+
+		_, errs := recorder.get()
+		if len(errs) != 1 || !errors.Is(errs[0], ErrQueueFull) {
+			t.Fatalf("expected a single ErrQueueFull, got %v", errs)
+		}
+	})
+}
+
+// TestSlowSubscriberDoesNotStallOthers ensures a stuck subscriber using a dropping
+// policy doesn't prevent delivery to the remaining subscribers.
+func TestSlowSubscriberDoesNotStallOthers(t *testing.T) {
+	streamer := NewStreamer()
+	_, _ = streamer.Subscribe("stuck", WithQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+	fast, _ := streamer.Subscribe("fast", WithQueueSize(10))
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			streamer.Publish(numberedEvent(i))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publishing stalled on a stuck subscriber")
+	}
+	if len(fast) != 10 {
+		t.Fatalf("expected 10 events queued for the fast subscriber, got %d", len(fast))
+	}
+}
+
+// TestUnsubscribeReleasesBlockedPublisher ensures a publisher blocked on a full queue
+// returns once the subscriber is removed.
+func TestUnsubscribeReleasesBlockedPublisher(t *testing.T) {
+	streamer := NewStreamer()
+	_, _ = streamer.Subscribe("stuck", WithQueueSize(0))
+
+	done := make(chan struct{})
+	go func() {
+		streamer.Publish(numberedEvent(0))
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	streamer.Unsubscribe("stuck")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher was not released by Unsubscribe")
+	}
+}
+
+// TestReplayHoldsBoundedEvents ensures live events held during a replay are bounded by the
+// queue size and handled according to the overflow policy.
+func TestReplayHoldsBoundedEvents(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	replaying := func(policy OverflowPolicy, recorder *overflowRecorder) *subscription {
+		sub := newSubscription("replaying", newSubscribeConfig([]SubscribeOption{WithQueueSize(2), WithOverflowPolicy(policy)}), recorder.handle)
+		sub.replaying.Store(true)
+		return sub
+	}
+
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected error
+		held     []interface{}
+		dropped  interface{}
+		reported error
+	}{
+		{"Drop newest", OverflowDropNewest, ErrEventDropped, []interface{}{0, 1}, 2, ErrEventDropped},
+		{"Drop oldest", OverflowDropOldest, nil, []interface{}{1, 2}, 0, ErrEventDropped},
+		{"Error", OverflowError, ErrQueueFull, []interface{}{0, 1}, 2, ErrQueueFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &overflowRecorder{}
+			sub := replaying(tt.policy, recorder)
+
+			for i := 0; i < 2; i++ {
+				if err := sub.deliver(context.Background(), numberedEvent(i), true); err != nil {
+					t.Fatalf("expected event %d to be held, got %v", i, err)
+				}
+			}
+			if err := sub.deliver(context.Background(), numberedEvent(2), true); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+
+			var held []interface{}
+			for _, event := range sub.pending {
+				held = append(held, event.Payload)
+			}
+			if !reflect.DeepEqual(held, tt.held) || sub.unprocessed.Load() != 2 {
+				t.Errorf("expected %v to be held and counted, got %v and %d", tt.held, held, sub.unprocessed.Load())
+			}
+			if events, errs := recorder.get(); len(events) != 1 || events[0].Payload != tt.dropped || !errors.Is(errs[0], tt.reported) {
+				t.Errorf("expected %v to be reported, got %v %v", tt.dropped, events, errs)
+			}
+		})
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		sub := replaying(OverflowBlock, &overflowRecorder{})
+		for i := 0; i < 2; i++ {
+			_ = sub.deliver(context.Background(), numberedEvent(i), true)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := sub.deliver(ctx, numberedEvent(2), true); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the publisher to wait while the held events fill the queue, got %v", err)
+		}
+
+		delivered := make(chan error, 1)
+		go func() { delivered <- sub.deliver(context.Background(), numberedEvent(3), true) }()
+		time.Sleep(20 * time.Millisecond)
+		go sub.replay(eventLog, 1, 1)
+
+		for _, expected := range []int{0, 1, 3} {
+			select {
+			case event := <-sub.ch:
+				if event.Payload != expected {
+					t.Errorf("expected payload %d, got %v", expected, event.Payload)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected payload %d to be queued", expected)
+			}
+		}
+		if err := <-delivered; err != nil {
+			t.Errorf("expected the publisher to be released by the replay, got %v", err)
+		}
+	})
+	t.Run("Closed", func(t *testing.T) {
+		sub := replaying(OverflowBlock, &overflowRecorder{})
+		for i := 0; i < 2; i++ {
+			_ = sub.deliver(context.Background(), numberedEvent(i), true)
+		}
+		sub.close()
+		sub.replay(eventLog, 1, 1)
+
+		if got := sub.unprocessed.Load(); got != 0 {
+			t.Errorf("expected the held events to be uncounted, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package sirkeji
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// PanicError describes a panic recovered from a Subscriber's Process method.
+//
+// It is published as the Payload of an Error event by the SubscriptionManager,
+// unless a custom PanicHandler is configured with WithPanicHandler.
+//
+// Fields:
+//   - SubscriberUid: The unique identifier of the panicking Subscriber.
+//   - Value: The value passed to panic.
+//   - Stack: The stack trace of the panicking goroutine.
+//   - Event: The Event being processed when the panic occurred.
+type PanicError struct {
+	SubscriberUid string
+	Value         interface{}
+	Stack         []byte
+	Event         Event
+}
+
+// Error implements the error interface.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("subscriber %s panicked while processing %s event: %v", p.SubscriberUid, p.Event.Type, p.Value)
+}
+
+// ErrorEvent creates the Error event describing the panic.
+//
+// Returns:
+//   - An Event with the `Error` EventType, published by the panicking Subscriber,
+//     caused by the offending Event and carrying the PanicError as its Payload.
+//
+// Example:
+//
+//	manager, _ := NewSubscriptionManager(streamer, subscriber, WithPanicHandler(func(p *PanicError) {
+//	    alerting.Notify(p)
+//	    streamer.Publish(p.ErrorEvent())
+//	}))
+func (p *PanicError) ErrorEvent() Event {
+	return p.Event.Derive(p.SubscriberUid, Error, p.Error(), p)
+}
+
+// PanicHandler is called with every panic recovered from a Subscriber's Process method.
+type PanicHandler func(p *PanicError)
+
+// WithPanicHandler replaces the default panic handling of the SubscriptionManager.
+//
+// By default a recovered panic is published back onto the Streamer as an Error event
+// created by PanicError.ErrorEvent, without waiting for subscribers whose queue is full.
+// Panics raised while processing such a report are only logged, never reported again.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithPanicHandler(func(p *PanicError) {
+//	    log.Printf("%v\n%s", p, p.Stack)
+//	}))
+func WithPanicHandler(handler PanicHandler) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.onPanic = handler
+	}
+}
+
+// withRecovery wraps process, recovering from any panic it raises.
+func (sm *SubscriptionManager) withRecovery(process ProcessFunc) ProcessFunc {
+	return func(event Event) {
+		defer func() {
+			if r := recover(); r != nil {
+				sm.handlePanic(&PanicError{
+					SubscriberUid: sm.subscriber.Uid(),
+					Value:         r,
+					Stack:         debug.Stack(),
+					Event:         event,
+				})
+			}
+		}()
+
+		process(event)
+	}
+}
+
+// handlePanic passes the panic to the configured PanicHandler or publishes it as an Error event.
+func (sm *SubscriptionManager) handlePanic(p *PanicError) {
+	if sm.metrics != nil {
+		sm.metrics.ProcessPanicked(p.SubscriberUid)
+	}
+
+	if sm.onPanic != nil {
+		sm.onPanic(p)
+		return
+	}
+
+	// Subscribers panicking on every event would keep reporting each other's panics forever.
+	if cause, ok := p.Event.Payload.(*PanicError); ok {
+		log.Printf("[%s] panicked while processing the panic report of %s: %v\n", p.SubscriberUid, cause.SubscriberUid, p.Value)
+		return
+	}
+
+	// Waiting for room in a full queue, maybe the subscriber's own, could block the
+	// processing goroutine for good, so subscribers without room miss the report.
+	if err := sm.streamer.PublishContext(context.Background(), p.ErrorEvent(), WithPublishMode(FireAndForget)); err != nil {
+		log.Printf("[%s] panic report not delivered: %v\n", p.SubscriberUid, err)
+	}
+}
@@ -0,0 +1,145 @@
+package sirkeji
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// PanickingSubscriber panics while processing events of a given type.
+type PanickingSubscriber struct {
+	*MockSubscriber
+	panicOn EventType
+}
+
+func (ps *PanickingSubscriber) Process(event Event) {
+	if event.Type == ps.panicOn {
+		_ = event.Payload.(int)
+	}
+	ps.MockSubscriber.Process(event)
+}
+
+// TestPanicRecovery ensures panics in Process are recovered and published as Error events.
+func TestPanicRecovery(t *testing.T) {
+	streamer := NewStreamer()
+	errorsCh, _ := streamer.Subscribe("error-watcher", WithEventTypes(Error))
+
+	subscriber := &PanickingSubscriber{MockSubscriber: NewMockSubscriber("panicking"), panicOn: Info}
+	subscribeWith(t, streamer, subscriber, WithSequentialProcessing())
+
+	event := Event{Publisher: "system", Type: Info, Meta: "bad payload", Payload: "not an int"}
+	streamer.Publish(event)
+
+	var reported Event
+	select {
+	case reported = <-errorsCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected an Error event for the recovered panic")
+	}
+
+	if reported.Publisher != "panicking" {
+		t.Errorf("expected Publisher 'panicking', got '%s'", reported.Publisher)
+	}
+	p, ok := reported.Payload.(*PanicError)
+	if !ok {
+		t.Fatalf("expected a *PanicError payload, got %T", reported.Payload)
+	}
+	if p.SubscriberUid != "panicking" {
+		t.Errorf("expected SubscriberUid 'panicking', got '%s'", p.SubscriberUid)
+	}
+	if p.Event.Meta != event.Meta {
+		t.Errorf("expected the offending event to be attached, got %+v", p.Event)
+	}
+	if !strings.Contains(string(p.Stack), "PanickingSubscriber") {
+		t.Errorf("expected the stack trace to mention the subscriber, got:\n%s", p.Stack)
+	}
+
+	// The subscriber keeps processing events after a panic.
+	streamer.Publish(Event{Publisher: "system", Type: Shutdown})
+	time.Sleep(50 * time.Millisecond)
+
+	if got := len(subscriber.GetProcessedEvents()); got != 2 {
+		t.Errorf("expected 2 processed events (Error report and Shutdown), got %d", got)
+	}
+}
+
+// TestCustomPanicHandler ensures a custom PanicHandler replaces the default Error event.
+func TestCustomPanicHandler(t *testing.T) {
+	streamer := NewStreamer()
+	errorsCh, _ := streamer.Subscribe("error-watcher", WithEventTypes(Error))
+
+	handled := make(chan *PanicError, 1)
+	subscriber := &PanickingSubscriber{MockSubscriber: NewMockSubscriber("panicking"), panicOn: Info}
+	subscribeWith(t, streamer, subscriber, WithPanicHandler(func(p *PanicError) {
+		handled <- p
+	}))
+
+	streamer.Publish(Event{Publisher: "system", Type: Info, Payload: "not an int"})
+
+	select {
+	case p := <-handled:
+		if p.SubscriberUid != "panicking" {
+			t.Errorf("expected SubscriberUid 'panicking', got '%s'", p.SubscriberUid)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the custom PanicHandler to be called")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if len(errorsCh) != 0 {
+		t.Errorf("expected no Error events with a custom PanicHandler, got %d", len(errorsCh))
+	}
+}
+
+// alwaysPanicking panics while processing any event, counting them.
+type alwaysPanicking struct {
+	*MockSubscriber
+	calls atomic.Int32
+}
+
+func (ap *alwaysPanicking) Process(Event) {
+	ap.calls.Add(1)
+	panic("always")
+}
+
+// TestPanicReportsDontLoop ensures subscribers panicking on every event don't report
+// each other's panics forever.
+func TestPanicReportsDontLoop(t *testing.T) {
+	streamer := NewStreamer()
+	first := &alwaysPanicking{MockSubscriber: NewMockSubscriber("first")}
+	second := &alwaysPanicking{MockSubscriber: NewMockSubscriber("second")}
+	subscribeWith(t, streamer, first, WithSequentialProcessing())
+	subscribeWith(t, streamer, second, WithSequentialProcessing())
+
+	streamer.Publish(InfoEvent("system", "hello"))
+	time.Sleep(100 * time.Millisecond)
+
+	// The Info event, then both panic reports.
+	if first.calls.Load() != 3 || second.calls.Load() != 3 {
+		t.Errorf("expected 3 events processed by each subscriber, got %d and %d", first.calls.Load(), second.calls.Load())
+	}
+}
+
+// TestPanicReportFullQueue ensures reporting a panic doesn't wait for the panicking
+// subscriber's own full queue.
+func TestPanicReportFullQueue(t *testing.T) {
+	streamer := NewStreamer()
+	subscriber := &PanickingSubscriber{MockSubscriber: NewMockSubscriber("panicking"), panicOn: Info}
+	subscribeWith(t, streamer, subscriber, WithSequentialProcessing(), WithSubscribeOptions(WithQueueSize(1)))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			streamer.Publish(Event{Publisher: "system", Type: Info, Payload: "not an int"})
+		}
+		streamer.Publish(Event{Publisher: "system", Type: Shutdown})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the panicking subscriber to keep processing events")
+	}
+}
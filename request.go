@@ -0,0 +1,325 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReplyToHeader is the header carrying the UID of the Requester waiting for replies to an event.
+const ReplyToHeader = "reply-to"
+
+// DefaultRequestTimeout bounds requests made with a context without a deadline.
+const DefaultRequestTimeout = 5 * time.Second
+
+var (
+	// ErrNoReply is returned when no reply is received before the request deadline.
+	ErrNoReply = errors.New("no reply received")
+
+	// ErrNotARequest is returned when replying to an event that wasn't published as a request.
+	ErrNotARequest = errors.New("event is not a request")
+
+	// ErrRequesterClosed is returned when making requests with a closed Requester.
+	ErrRequesterClosed = errors.New("requester closed")
+
+	// ErrNoSubscribers is returned, wrapped with ErrNoReply, when a request wasn't
+	// received by any subscriber.
+	ErrNoSubscribers = errors.New("request not received by any subscriber")
+)
+
+// Requester publishes request events and waits for the Reply events published in
+// response to them.
+//
+// Every Requester subscribes to the Streamer once, as an inbox receiving Reply events,
+// and matches replies to pending requests by their CausationID. Subscribers answer
+// requests with Respond or NewReply.
+type Requester struct {
+	// streamer is the Streamer requests are published to.
+	streamer Streamer
+	// uid identifies the inbox subscription, it is set as the ReplyToHeader of requests.
+	uid string
+	// timeout bounds requests made with a context without a deadline.
+	timeout time.Duration
+
+	// pending maps the IDs of the requests waiting for replies to their replies.
+	pending map[string]*pendingRequest
+	// done is closed when the Requester is closed.
+	done chan struct{}
+	mu   sync.Mutex
+}
+
+// pendingRequest collects the replies to a single request.
+type pendingRequest struct {
+	replies []Event
+	// notify is signalled whenever a reply is added.
+	notify chan struct{}
+	mu     sync.Mutex
+}
+
+// add records a reply and signals the waiting request.
+func (p *pendingRequest) add(reply Event) {
+	p.mu.Lock()
+	p.replies = append(p.replies, reply)
+	p.mu.Unlock()
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// collected returns the replies received so far.
+func (p *pendingRequest) collected() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Event(nil), p.replies...)
+}
+
+// RequesterOption configures a Requester created by NewRequester.
+type RequesterOption func(*Requester)
+
+// WithRequestTimeout sets the deadline of requests made with a context without a deadline.
+//
+// Without this option DefaultRequestTimeout is used.
+//
+// Example:
+//
+//	requester, err := NewRequester(streamer, "pricing-client", WithRequestTimeout(time.Second))
+func WithRequestTimeout(timeout time.Duration) RequesterOption {
+	return func(r *Requester) {
+		r.timeout = timeout
+	}
+}
+
+// NewRequester creates a Requester and subscribes its inbox to the Streamer.
+//
+// Parameters:
+//   - streamer: The Streamer requests are published to. Must not be nil.
+//   - uid: The unique identifier of the Requester's inbox subscription.
+//   - opts: Optional RequesterOptions.
+//
+// Returns:
+//   - A pointer to a new Requester.
+//   - An error if the streamer is nil or the inbox couldn't be subscribed (e.g., duplicate UID).
+//
+// Example:
+//
+//	requester, err := NewRequester(streamer, "pricing-client")
+//	if err != nil {
+//	    log.Fatalf("failed to create requester: %v", err)
+//	}
+//	defer requester.Close()
+func NewRequester(streamer Streamer, uid string, opts ...RequesterOption) (*Requester, error) {
+	if streamer == nil {
+		return nil, ErrStreamerShouldNotBeNil
+	}
+
+	r := &Requester{
+		streamer: streamer,
+		uid:      uid,
+		timeout:  DefaultRequestTimeout,
+		pending:  make(map[string]*pendingRequest),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	ch, err := streamer.Subscribe(uid, WithEventTypes(Reply))
+	if err != nil {
+		return nil, err
+	}
+	go r.receive(ch)
+
+	return r, nil
+}
+
+// receive hands the replies received by the inbox over to the pending requests.
+func (r *Requester) receive(ch chan Event) {
+	for event := range ch {
+		if event.Header(ReplyToHeader) != r.uid {
+			continue
+		}
+
+		r.mu.Lock()
+		p, ok := r.pending[event.CausationID]
+		r.mu.Unlock()
+
+		if ok {
+			p.add(event)
+		}
+	}
+}
+
+// Request publishes the event as a request and waits for the first reply.
+//
+// Parameters:
+//   - ctx: Bounds the time spent waiting for a reply. Without a deadline, the
+//     Requester's timeout is applied.
+//   - event: The request event.
+//
+// Returns:
+//   - The first Reply event published in response to the request.
+//   - ErrNoReply if nobody replied before the deadline, wrapping ErrNoSubscribers
+//     right away if no subscriber received the request. ErrRequesterClosed if the
+//     Requester was closed, or the error returned by the Streamer when publishing.
+//
+// Example:
+//
+//	reply, err := requester.Request(ctx, NewEvent("checkout", PriceQuery, "", sku))
+//	if errors.Is(err, ErrNoReply) {
+//	    log.Printf("pricing service didn't answer")
+//	}
+func (r *Requester) Request(ctx context.Context, event Event) (Event, error) {
+	replies, err := r.Gather(ctx, event, 1)
+	if err != nil {
+		return Event{}, err
+	}
+	return replies[0], nil
+}
+
+// Gather publishes the event as a request and collects replies until n replies were
+// received or the deadline is reached (scatter-gather).
+//
+// Parameters:
+//   - ctx: Bounds the time spent collecting replies. Without a deadline, the
+//     Requester's timeout is applied.
+//   - event: The request event.
+//   - n: The number of replies to wait for. Values below 1 collect replies until the deadline.
+//
+// Returns:
+//   - The replies in the order they were received, possibly fewer than n if the deadline
+//     was reached first.
+//   - ErrNoReply if nobody replied before the deadline, wrapping ErrNoSubscribers
+//     right away if no subscriber received the request. ErrRequesterClosed if the
+//     Requester was closed, or the error returned by the Streamer when publishing.
+//
+// Behavior:
+//   - Stamps the event and sets its ReplyToHeader, replies are matched by CausationID.
+//   - Subscribers that didn't receive the request (see DeliveryError) are simply not
+//     waited for.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+//	defer cancel()
+//
+//	quotes, err := requester.Gather(ctx, NewEvent("checkout", QuoteRequest, "", order), 3)
+func (r *Requester) Gather(ctx context.Context, event Event, n int) ([]Event, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	event = stampEvent(inheritCause(ctx, event)).WithHeader(ReplyToHeader, r.uid)
+	p := &pendingRequest{notify: make(chan struct{}, 1)}
+
+	r.mu.Lock()
+	select {
+	case <-r.done:
+		r.mu.Unlock()
+		return nil, ErrRequesterClosed
+	default:
+	}
+	r.pending[event.ID] = p
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, event.ID)
+		r.mu.Unlock()
+	}()
+
+	delivered := -1
+	var deliveryErr *DeliveryError
+	if err := r.streamer.PublishContext(ctx, event, withDeliveredCount(&delivered)); err != nil && !errors.As(err, &deliveryErr) {
+		return nil, err
+	}
+	// Nobody can reply to a request nobody received, there's no point in waiting.
+	if delivered == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrNoReply, ErrNoSubscribers)
+	}
+
+	for n < 1 || len(p.collected()) < n {
+		select {
+		case <-p.notify:
+		case <-r.done:
+			return nil, ErrRequesterClosed
+		case <-ctx.Done():
+			replies := p.collected()
+			if len(replies) == 0 {
+				return nil, fmt.Errorf("%w: %w", ErrNoReply, ctx.Err())
+			}
+			return replies, nil
+		}
+	}
+	return p.collected()[:n], nil
+}
+
+// Close unsubscribes the Requester's inbox, pending requests fail with ErrRequesterClosed.
+func (r *Requester) Close() {
+	r.mu.Lock()
+	select {
+	case <-r.done:
+		r.mu.Unlock()
+		return
+	default:
+	}
+	close(r.done)
+	r.mu.Unlock()
+
+	r.streamer.Unsubscribe(r.uid)
+}
+
+// NewReply creates the Reply event answering a request.
+//
+// Parameters:
+//   - request: The request event being answered.
+//   - publisher: The origin of the reply.
+//   - meta: Optional metadata describing the reply.
+//   - payload: Optional data associated with the reply.
+//
+// Returns:
+//   - A Reply event derived from the request, addressed to the requester.
+//   - ErrNotARequest if the event wasn't published as a request.
+//
+// Example:
+//
+//	reply, err := NewReply(event, p.uid, "", price)
+func NewReply(request Event, publisher, meta string, payload interface{}) (Event, error) {
+	replyTo := request.Header(ReplyToHeader)
+	if replyTo == "" {
+		return Event{}, ErrNotARequest
+	}
+	return request.Derive(publisher, Reply, meta, payload).WithHeader(ReplyToHeader, replyTo), nil
+}
+
+// Respond publishes the Reply event answering a request.
+//
+// Parameters:
+//   - publish: The function used to publish the reply, e.g. streamer.Publish.
+//   - request: The request event being answered.
+//   - publisher: The origin of the reply.
+//   - meta: Optional metadata describing the reply.
+//   - payload: Optional data associated with the reply.
+//
+// Returns:
+//   - ErrNotARequest if the event wasn't published as a request, nil otherwise.
+//
+// Example:
+//
+//	func (p *PricingService) Process(event sirkeji.Event) {
+//	    _ = sirkeji.Respond(p.publish, event, p.uid, "", p.price(event.Payload.(string)))
+//	}
+func Respond(publish func(Event), request Event, publisher, meta string, payload interface{}) error {
+	reply, err := NewReply(request, publisher, meta, payload)
+	if err != nil {
+		return err
+	}
+	publish(reply)
+	return nil
+}
@@ -0,0 +1,148 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const requestTestQuestion EventType = "RequestTestQuestion"
+
+// responder answers every request with its uid as payload.
+type responder struct {
+	*MockSubscriber
+	publish func(Event)
+}
+
+func newResponder(uid string, streamer Streamer) *responder {
+	return &responder{MockSubscriber: NewMockSubscriber(uid), publish: streamer.Publish}
+}
+
+func (r *responder) EventTypes() []EventType {
+	return []EventType{requestTestQuestion}
+}
+
+func (r *responder) Process(event Event) {
+	r.MockSubscriber.Process(event)
+	_ = Respond(r.publish, event, r.Uid(), "", r.Uid())
+}
+
+func newTestRequester(t *testing.T, streamer Streamer, opts ...RequesterOption) *Requester {
+	t.Helper()
+
+	requester, err := NewRequester(streamer, "requester", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(requester.Close)
+	return requester
+}
+
+// TestRequest ensures a request receives the reply of a subscriber.
+func TestRequest(t *testing.T) {
+	streamer := NewStreamer()
+	subscribeWith(t, streamer, newResponder("responder", streamer))
+	requester := newTestRequester(t, streamer)
+
+	request := NewEvent("client", requestTestQuestion, "", nil)
+	reply, err := requester.Request(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reply.Type != Reply || reply.Payload != "responder" {
+		t.Errorf("expected a Reply from 'responder', got %+v", reply)
+	}
+	if reply.CausationID != request.ID || reply.CorrelationID != request.ID {
+		t.Errorf("expected the reply to be caused by the request, got %+v", reply)
+	}
+}
+
+// TestRequestNoReply ensures requests nobody answers fail once the deadline is reached.
+func TestRequestNoReply(t *testing.T) {
+	streamer := NewStreamer()
+	_, _ = streamer.Subscribe("silent", WithEventTypes(requestTestQuestion))
+	requester := newTestRequester(t, streamer, WithRequestTimeout(20*time.Millisecond))
+
+	_, err := requester.Request(context.Background(), NewEvent("client", requestTestQuestion, "", nil))
+	if !errors.Is(err, ErrNoReply) {
+		t.Errorf("expected ErrNoReply, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestRequestNoSubscribers ensures requests nobody receives fail without waiting for the deadline.
+func TestRequestNoSubscribers(t *testing.T) {
+	requester := newTestRequester(t, NewStreamer())
+
+	start := time.Now()
+	_, err := requester.Request(context.Background(), NewEvent("client", requestTestQuestion, "", nil))
+	if !errors.Is(err, ErrNoReply) || !errors.Is(err, ErrNoSubscribers) {
+		t.Errorf("expected ErrNoReply wrapping ErrNoSubscribers, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the request to fail right away, took %v", elapsed)
+	}
+}
+
+// TestGather ensures replies are collected until enough were received or the deadline is reached.
+func TestGather(t *testing.T) {
+	streamer := NewStreamer()
+	for _, uid := range []string{"responder-1", "responder-2", "responder-3"} {
+		subscribeWith(t, streamer, newResponder(uid, streamer))
+	}
+	requester := newTestRequester(t, streamer)
+
+	t.Run("Enough replies", func(t *testing.T) {
+		replies, err := requester.Gather(context.Background(), NewEvent("client", requestTestQuestion, "", nil), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(replies) != 2 {
+			t.Errorf("expected 2 replies, got %d", len(replies))
+		}
+	})
+
+	t.Run("Until the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		replies, err := requester.Gather(ctx, NewEvent("client", requestTestQuestion, "", nil), 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(replies) != 3 {
+			t.Errorf("expected 3 replies, got %d", len(replies))
+		}
+	})
+}
+
+// TestRespondToNonRequest ensures only requests can be replied to.
+func TestRespondToNonRequest(t *testing.T) {
+	published := false
+	err := Respond(func(Event) { published = true }, InfoEvent("system", "hello"), "responder", "", nil)
+
+	if !errors.Is(err, ErrNotARequest) {
+		t.Errorf("expected ErrNotARequest, got %v", err)
+	}
+	if published {
+		t.Error("expected no reply to be published")
+	}
+}
+
+// TestRequesterClose ensures a closed Requester rejects requests and frees its UID.
+func TestRequesterClose(t *testing.T) {
+	streamer := NewStreamer()
+	requester := newTestRequester(t, streamer)
+	requester.Close()
+
+	if _, err := requester.Request(context.Background(), NewEvent("client", requestTestQuestion, "", nil)); !errors.Is(err, ErrRequesterClosed) {
+		t.Errorf("expected ErrRequesterClosed, got %v", err)
+	}
+	if _, err := streamer.Subscribe("requester"); err != nil {
+		t.Errorf("expected the inbox to be unsubscribed, got %v", err)
+	}
+}
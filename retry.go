@@ -0,0 +1,272 @@
+package sirkeji
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrorProcessor is an alternative to Subscriber for components whose processing may fail.
+//
+// Wrap it with NewErrorSubscriber to subscribe it; the SubscriptionManager then retries
+// failed events according to its RetryPolicy and dead-letters the events that still fail,
+// see WithRetry and WithDeadLetterHandler.
+type ErrorProcessor interface {
+	// Uid returns the unique identifier of the processor.
+	Uid() string
+
+	// Process handles the received event.
+	//
+	// Returns:
+	//   - nil if the event was processed, an error otherwise. Errors wrapped with
+	//     Permanent are not retried.
+	Process(event Event) error
+
+	// Subscribed is called when the processor is successfully connected to the Streamer.
+	Subscribed()
+
+	// Unsubscribed is called when the processor is disconnected from the Streamer.
+	Unsubscribed()
+}
+
+// ErrorSubscriber is a Subscriber whose processing may fail, such as the one returned by
+// NewErrorSubscriber.
+//
+// When a Subscriber implements it, the SubscriptionManager calls ProcessErr rather than
+// Process, retrying failed events according to its RetryPolicy and dead-lettering the
+// events that still fail. Wrappers around a Subscriber returned by NewErrorSubscriber
+// should implement it too, to keep retries enabled.
+type ErrorSubscriber interface {
+	Subscriber
+
+	// ProcessErr handles the received event, like Process.
+	//
+	// Returns:
+	//   - nil if the event was processed, an error otherwise. Errors wrapped with
+	//     Permanent are not retried.
+	ProcessErr(event Event) error
+}
+
+// errorSubscriber adapts an ErrorProcessor to the ErrorSubscriber interface.
+type errorSubscriber struct {
+	processor ErrorProcessor
+}
+
+// NewErrorSubscriber wraps an ErrorProcessor into a Subscriber.
+//
+// If the processor declares EventTypes like a FilteredSubscriber, only those types are
+// routed to it.
+//
+// Example:
+//
+//	sirkeji.Subscribe(streamer, sirkeji.NewErrorSubscriber(mailer),
+//	    sirkeji.WithRetry(sirkeji.ExponentialRetry(5, 100*time.Millisecond, 10*time.Second, 0.2)))
+func NewErrorSubscriber(processor ErrorProcessor) ErrorSubscriber {
+	return &errorSubscriber{processor: processor}
+}
+
+// Uid returns the unique identifier of the wrapped processor.
+func (s *errorSubscriber) Uid() string {
+	return s.processor.Uid()
+}
+
+// EventTypes returns the EventTypes declared by the wrapped processor, if any.
+func (s *errorSubscriber) EventTypes() []EventType {
+	if filtered, ok := s.processor.(interface{ EventTypes() []EventType }); ok {
+		return filtered.EventTypes()
+	}
+	return nil
+}
+
+// Process calls the wrapped processor, discarding its error.
+//
+// The SubscriptionManager calls ProcessErr instead, to act on the error.
+func (s *errorSubscriber) Process(event Event) {
+	_ = s.processor.Process(event)
+}
+
+// ProcessErr calls the wrapped processor.
+func (s *errorSubscriber) ProcessErr(event Event) error {
+	return s.processor.Process(event)
+}
+
+// Subscribed calls the wrapped processor.
+func (s *errorSubscriber) Subscribed() {
+	s.processor.Subscribed()
+}
+
+// Unsubscribed calls the wrapped processor.
+func (s *errorSubscriber) Unsubscribed() {
+	s.processor.Unsubscribed()
+}
+
+// permanentError marks an error as not worth retrying.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps an error returned by ErrorProcessor.Process, so the event is
+// dead-lettered right away instead of being retried.
+//
+// Example:
+//
+//	if err := json.Unmarshal(data, &order); err != nil {
+//	    return sirkeji.Permanent(err)
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether the error, or any error it wraps, was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// RetryPolicy decides whether and when failed events are processed again.
+type RetryPolicy interface {
+	// Backoff is called after every failed attempt.
+	//
+	// Parameters:
+	//   - attempt: The number of the failed attempt, starting at 1.
+	//   - err: The error returned by the failed attempt.
+	//
+	// Returns:
+	//   - The delay before the next attempt.
+	//   - false if the event shouldn't be processed again.
+	Backoff(attempt int, err error) (time.Duration, bool)
+}
+
+// NoRetry never retries, failed events are dead-lettered after their first attempt.
+// It is the default RetryPolicy of a SubscriptionManager.
+var NoRetry RetryPolicy = fixedRetry{maxAttempts: 1}
+
+// fixedRetry waits the same delay between attempts.
+type fixedRetry struct {
+	maxAttempts int
+	delay       time.Duration
+}
+
+// FixedRetry retries failed events after a fixed delay.
+//
+// Parameters:
+//   - maxAttempts: The maximum number of attempts, including the first one.
+//   - delay: The delay between attempts.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber, WithRetry(FixedRetry(3, time.Second)))
+func FixedRetry(maxAttempts int, delay time.Duration) RetryPolicy {
+	return fixedRetry{maxAttempts: maxAttempts, delay: delay}
+}
+
+func (p fixedRetry) Backoff(attempt int, err error) (time.Duration, bool) {
+	return p.delay, attempt < p.maxAttempts
+}
+
+// exponentialRetry doubles the delay after every attempt.
+type exponentialRetry struct {
+	maxAttempts int
+	base        time.Duration
+	maxDelay    time.Duration
+	jitter      float64
+}
+
+// ExponentialRetry retries failed events with an exponentially growing delay.
+//
+// Parameters:
+//   - maxAttempts: The maximum number of attempts, including the first one.
+//   - base: The delay before the first retry, doubled for every following retry.
+//   - maxDelay: The upper bound of the delay, zero for no bound.
+//   - jitter: The fraction of the delay randomly cut off, between 0 and 1, so
+//     subscribers failing together don't retry together.
+//
+// Example:
+//
+//	// Retries after ~100ms, ~200ms, ~400ms and ~800ms.
+//	policy := ExponentialRetry(5, 100*time.Millisecond, 10*time.Second, 0.2)
+func ExponentialRetry(maxAttempts int, base, maxDelay time.Duration, jitter float64) RetryPolicy {
+	return exponentialRetry{
+		maxAttempts: maxAttempts,
+		base:        base,
+		maxDelay:    maxDelay,
+		jitter:      min(max(jitter, 0), 1),
+	}
+}
+
+func (p exponentialRetry) Backoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.maxAttempts {
+		return 0, false
+	}
+
+	delay := p.base
+	for i := 1; i < attempt && (p.maxDelay == 0 || delay < p.maxDelay); i++ {
+		delay *= 2
+	}
+	if p.maxDelay > 0 && delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	if p.jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.jitter * float64(delay))
+	}
+	return delay, true
+}
+
+// WithRetry sets the RetryPolicy applied to the events an ErrorProcessor fails to process.
+//
+// It only applies to Subscribers created with NewErrorSubscriber. Retries are made by
+// the goroutine processing the event, so they delay the following events in the
+// ProcessSequential and ProcessKeyed modes.
+//
+// Example:
+//
+//	sirkeji.Subscribe(streamer, sirkeji.NewErrorSubscriber(mailer),
+//	    sirkeji.WithRetry(sirkeji.FixedRetry(3, time.Second)))
+func WithRetry(policy RetryPolicy) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.retryPolicy = policy
+	}
+}
+
+// withRetries turns an error returning process function into a ProcessFunc, retrying
+// failed events and dead-lettering those that can't be processed.
+func (sm *SubscriptionManager) withRetries(process func(event Event) error) ProcessFunc {
+	policy := sm.retryPolicy
+	if policy == nil {
+		policy = NoRetry
+	}
+
+	return func(event Event) {
+		var attempts []Attempt
+		for attempt := 1; ; attempt++ {
+			start := sm.clock.Now()
+			err := process(event)
+			if err == nil {
+				return
+			}
+			attempts = append(attempts, Attempt{Number: attempt, Time: start, Duration: sm.clock.Since(start), Err: err})
+
+			if IsPermanent(err) {
+				break
+			}
+			delay, retry := policy.Backoff(attempt, err)
+			if !retry {
+				break
+			}
+			sm.clock.Sleep(delay)
+		}
+
+		sm.handleDeadLetter(newDeadLetterRecord(sm.subscriber.Uid(), event, attempts, sm.clock.Now()))
+	}
+}
@@ -0,0 +1,183 @@
+package sirkeji
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTestFailure = errors.New("test failure")
+
+// flakyProcessor fails the first failures attempts of every Info event, by Meta.
+type flakyProcessor struct {
+	uid      string
+	failures int
+	err      error
+	attempts map[string]int
+	sync.Mutex
+}
+
+func newFlakyProcessor(uid string, failures int, err error) *flakyProcessor {
+	return &flakyProcessor{uid: uid, failures: failures, err: err, attempts: map[string]int{}}
+}
+
+func (p *flakyProcessor) Uid() string {
+	return p.uid
+}
+
+func (p *flakyProcessor) EventTypes() []EventType {
+	return []EventType{Info}
+}
+
+func (p *flakyProcessor) Process(event Event) error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.attempts[event.Meta]++
+	if p.attempts[event.Meta] <= p.failures {
+		return fmt.Errorf("attempt %d: %w", p.attempts[event.Meta], p.err)
+	}
+	return nil
+}
+
+func (p *flakyProcessor) attemptsOf(event Event) int {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.attempts[event.Meta]
+}
+
+func (p *flakyProcessor) Subscribed() {}
+
+func (p *flakyProcessor) Unsubscribed() {}
+
+// deadLetterRecorder collects the records passed to a DeadLetterHandler.
+func deadLetterRecorder() (DeadLetterHandler, chan *DeadLetterRecord) {
+	records := make(chan *DeadLetterRecord, 10)
+	return func(record *DeadLetterRecord) { records <- record }, records
+}
+
+// TestRetry ensures failed events are retried according to the RetryPolicy.
+func TestRetry(t *testing.T) {
+	t.Run("Succeeds within the attempts", func(t *testing.T) {
+		streamer := NewStreamer()
+		processor := newFlakyProcessor("flaky", 2, errTestFailure)
+		handler, records := deadLetterRecorder()
+		subscribeWith(t, streamer, NewErrorSubscriber(processor),
+			WithSequentialProcessing(), WithRetry(FixedRetry(3, time.Millisecond)), WithDeadLetterHandler(handler))
+
+		event := InfoEvent("system", "hello")
+		streamer.Publish(event)
+		time.Sleep(50 * time.Millisecond)
+
+		if got := processor.attemptsOf(event); got != 3 {
+			t.Errorf("expected 3 attempts, got %d", got)
+		}
+		if len(records) != 0 {
+			t.Errorf("expected no dead letters, got %d", len(records))
+		}
+	})
+
+	t.Run("Exhausts the attempts", func(t *testing.T) {
+		streamer := NewStreamer()
+		processor := newFlakyProcessor("failing", 10, errTestFailure)
+		handler, records := deadLetterRecorder()
+		subscribeWith(t, streamer, NewErrorSubscriber(processor),
+			WithRetry(FixedRetry(3, time.Millisecond)), WithDeadLetterHandler(handler))
+
+		event := InfoEvent("system", "hello")
+		streamer.Publish(event)
+
+		var record *DeadLetterRecord
+		select {
+		case record = <-records:
+		case <-time.After(time.Second):
+			t.Fatal("expected the event to be dead-lettered")
+		}
+
+		if len(record.Attempts) != 3 || record.Attempts[2].Number != 3 {
+			t.Errorf("expected 3 attempts, got %+v", record.Attempts)
+		}
+		if record.SubscriberUid != "failing" || record.Event.ID != event.ID {
+			t.Errorf("expected the record to describe the failed event, got %+v", record)
+		}
+		if !errors.Is(record, errTestFailure) {
+			t.Errorf("expected the record to wrap the failure, got %v", record.Err)
+		}
+	})
+
+	t.Run("Permanent errors", func(t *testing.T) {
+		streamer := NewStreamer()
+		processor := newFlakyProcessor("permanent", 10, Permanent(errTestFailure))
+		handler, records := deadLetterRecorder()
+		subscribeWith(t, streamer, NewErrorSubscriber(processor),
+			WithRetry(FixedRetry(5, time.Millisecond)), WithDeadLetterHandler(handler))
+
+		streamer.Publish(InfoEvent("system", "hello"))
+
+		select {
+		case record := <-records:
+			if len(record.Attempts) != 1 {
+				t.Errorf("expected a single attempt, got %d", len(record.Attempts))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the event to be dead-lettered")
+		}
+	})
+
+	t.Run("Wrapped ErrorSubscriber", func(t *testing.T) {
+		streamer := NewStreamer()
+		processor := newFlakyProcessor("wrapped", 2, errTestFailure)
+		subscribeWith(t, streamer, wrappedErrorSubscriber{NewErrorSubscriber(processor)},
+			WithSequentialProcessing(), WithRetry(FixedRetry(3, time.Millisecond)))
+
+		event := InfoEvent("system", "hello")
+		streamer.Publish(event)
+		time.Sleep(50 * time.Millisecond)
+
+		if got := processor.attemptsOf(event); got != 3 {
+			t.Errorf("expected 3 attempts through the wrapper, got %d", got)
+		}
+	})
+}
+
+// wrappedErrorSubscriber wraps an ErrorSubscriber, like an application decorator would.
+type wrappedErrorSubscriber struct {
+	ErrorSubscriber
+}
+
+// TestRetryPolicies ensures the built-in policies compute the expected delays.
+func TestRetryPolicies(t *testing.T) {
+	t.Run("No retry", func(t *testing.T) {
+		if _, retry := NoRetry.Backoff(1, errTestFailure); retry {
+			t.Error("expected NoRetry not to retry")
+		}
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		policy := ExponentialRetry(6, 100*time.Millisecond, time.Second, 0)
+		expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+
+		for i, want := range expected {
+			delay, retry := policy.Backoff(i+1, errTestFailure)
+			if !retry || delay != want {
+				t.Errorf("attempt %d: expected a retry after %v, got %v (retry: %v)", i+1, want, delay, retry)
+			}
+		}
+		if _, retry := policy.Backoff(6, errTestFailure); retry {
+			t.Error("expected no retry after the last attempt")
+		}
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		policy := ExponentialRetry(10, 100*time.Millisecond, 0, 0.5)
+		for i := 0; i < 100; i++ {
+			delay, _ := policy.Backoff(3, errTestFailure)
+			if delay < 200*time.Millisecond || delay > 400*time.Millisecond {
+				t.Fatalf("expected a delay between 200ms and 400ms, got %v", delay)
+			}
+		}
+	})
+}
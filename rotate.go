@@ -0,0 +1,321 @@
+package sirkeji
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// archiveTimeFormat is the timestamp format of archive names, sorting in time order.
+const archiveTimeFormat = "20060102T150405.000000000"
+
+// RotatingFile is an io.Writer appending to a file, which is archived and replaced by
+// a new file once it grows too large or too old.
+//
+// Archives are named after the file and the time of the rotation, e.g. the archives of
+// "app.log" are "app-20250102T150405.000000000.log", or "app-20250102T150405.000000000.log.gz"
+// when compressed. The file is opened on the first write, and it is safe for concurrent use.
+type RotatingFile struct {
+	path        string
+	maxSize     int64
+	interval    time.Duration
+	maxArchives int
+	compress    bool
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	mu       sync.Mutex
+
+	// archiving tracks the archives being compressed and pruned in the background.
+	archiving sync.WaitGroup
+	archiveMu sync.Mutex
+}
+
+// RotateOption configures a RotatingFile.
+type RotateOption func(*RotatingFile)
+
+// WithMaxSize rotates the file before a write would grow it beyond the given size in bytes.
+//
+// Example:
+//
+//	file := NewRotatingFile("app.log", WithMaxSize(10<<20)) // 10 MiB
+func WithMaxSize(size int64) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxSize = size
+	}
+}
+
+// WithRotateInterval rotates the file once it has been written to for the given duration.
+// A file left by a previous run is aged from its modification time.
+//
+// Example:
+//
+//	file := NewRotatingFile("app.log", WithRotateInterval(24*time.Hour))
+func WithRotateInterval(interval time.Duration) RotateOption {
+	return func(f *RotatingFile) {
+		f.interval = interval
+	}
+}
+
+// WithMaxArchives keeps only the given number of most recent archives, deleting older ones.
+// By default every archive is kept.
+//
+// Example:
+//
+//	file := NewRotatingFile("app.log", WithMaxSize(10<<20), WithMaxArchives(5))
+func WithMaxArchives(n int) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxArchives = n
+	}
+}
+
+// WithCompression compresses the archives with gzip, in the background.
+//
+// Example:
+//
+//	file := NewRotatingFile("app.log", WithRotateInterval(time.Hour), WithCompression())
+func WithCompression() RotateOption {
+	return func(f *RotatingFile) {
+		f.compress = true
+	}
+}
+
+// NewRotatingFile creates a RotatingFile writing to the file at the given path.
+//
+// Without WithMaxSize or WithRotateInterval the file is only rotated by calling Rotate.
+//
+// Parameters:
+//   - path: The path of the file, its directory is created if needed.
+//   - opts: Optional RotateOptions configuring the rotation.
+//
+// Returns:
+//   - A pointer to a new RotatingFile, the file is opened on the first write.
+//
+// Example:
+//
+//	file := NewRotatingFile("/var/log/app/events.log",
+//	    WithMaxSize(50<<20), WithMaxArchives(10), WithCompression())
+//	defer file.Close()
+func NewRotatingFile(path string, opts ...RotateOption) *RotatingFile {
+	f := &RotatingFile{path: path}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Write appends p to the file, rotating it first if needed.
+//
+// Returns:
+//   - The number of bytes written and the error opening, rotating or writing the file, if any.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.due(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// due reports whether the file must be rotated before writing n more bytes.
+func (f *RotatingFile) due(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.maxSize > 0 && f.size+int64(n) > f.maxSize {
+		return true
+	}
+	return f.interval > 0 && time.Since(f.openedAt) >= f.interval
+}
+
+// open opens the file for appending. The caller must hold the lock.
+//
+// An existing file is aged from its modification time, so restarting the process
+// doesn't postpone its rotation.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Rotate archives the current file and starts a new one.
+//
+// Returns:
+//   - The error closing, renaming or reopening the file, if any.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rotate()
+}
+
+// rotate archives the current file and opens a new one. The caller must hold the lock.
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+
+	if _, err := os.Stat(f.path); err == nil {
+		archive := f.archiveName(time.Now())
+		if err := os.Rename(f.path, archive); err != nil {
+			return err
+		}
+		if f.compress {
+			f.archiving.Add(1)
+			go func() {
+				defer f.archiving.Done()
+				f.archive(archive)
+			}()
+		} else {
+			f.archive(archive)
+		}
+	}
+
+	return f.open()
+}
+
+// archiveName returns an unused archive name for a rotation at the given time.
+func (f *RotatingFile) archiveName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	for {
+		name := fmt.Sprintf("%s-%s%s", base, t.UTC().Format(archiveTimeFormat), ext)
+		if _, err := os.Stat(name); errors.Is(err, os.ErrNotExist) {
+			if _, err := os.Stat(name + ".gz"); errors.Is(err, os.ErrNotExist) {
+				return name
+			}
+		}
+		t = t.Add(time.Nanosecond)
+	}
+}
+
+// archive compresses the new archive if configured, and deletes the archives in excess.
+func (f *RotatingFile) archive(name string) {
+	f.archiveMu.Lock()
+	defer f.archiveMu.Unlock()
+
+	if f.compress {
+		if err := compressFile(name); err != nil {
+			log.Printf("[rotating-file] failed to compress %s: %v\n", name, err)
+		}
+	}
+	if f.maxArchives <= 0 {
+		return
+	}
+
+	archives, err := f.Archives()
+	if err != nil {
+		log.Printf("[rotating-file] failed to list the archives of %s: %v\n", f.path, err)
+		return
+	}
+	for len(archives) > f.maxArchives {
+		if err := os.Remove(archives[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("[rotating-file] failed to delete %s: %v\n", archives[0], err)
+		}
+		archives = archives[1:]
+	}
+}
+
+// compressFile replaces the file with its gzip compressed copy.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(name + ".gz")
+		return err
+	}
+	return os.Remove(name)
+}
+
+// Archives returns the paths of the archives of the file, oldest first.
+func (f *RotatingFile) Archives() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	archives := matches[:0]
+	for _, match := range matches {
+		stamp := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(match, ".gz"), ext), base+"-")
+		if _, err := time.Parse(archiveTimeFormat, stamp); err == nil {
+			archives = append(archives, match)
+		}
+	}
+	sort.Strings(archives)
+	return archives, nil
+}
+
+// Close closes the file and waits for the archives being compressed.
+//
+// The file is reopened by the next write.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.archiving.Wait()
+	return err
+}
@@ -0,0 +1,202 @@
+package sirkeji
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRotatingFileSize ensures the file is rotated before it grows beyond the maximum size.
+func TestRotatingFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file := NewRotatingFile(path, WithMaxSize(10))
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	archives, err := file.Archives()
+	if err != nil {
+		t.Fatalf("Failed to list archives: %v", err)
+	}
+	if len(archives) != 2 {
+		t.Fatalf("Expected 2 archives, got %v", archives)
+	}
+	for i, expected := range []string{"first\n", "second\n"} {
+		if content := readFile(t, archives[i]); content != expected {
+			t.Errorf("Expected archive %d to contain %q, got %q", i, expected, content)
+		}
+	}
+	if content := readFile(t, path); content != "third\n" {
+		t.Errorf("Expected the file to contain %q, got %q", "third\n", content)
+	}
+}
+
+// TestRotatingFileInterval ensures the file is rotated once it gets older than the interval.
+func TestRotatingFileInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file := NewRotatingFile(path, WithRotateInterval(20*time.Millisecond))
+	defer file.Close()
+
+	_, _ = file.Write([]byte("old\n"))
+	_, _ = file.Write([]byte("still old\n"))
+	time.Sleep(30 * time.Millisecond)
+	_, _ = file.Write([]byte("new\n"))
+
+	archives, _ := file.Archives()
+	if len(archives) != 1 {
+		t.Fatalf("Expected 1 archive, got %v", archives)
+	}
+	if content := readFile(t, archives[0]); content != "old\nstill old\n" {
+		t.Errorf("Unexpected archive content: %q", content)
+	}
+	if content := readFile(t, path); content != "new\n" {
+		t.Errorf("Unexpected file content: %q", content)
+	}
+}
+
+// TestRotatingFileIntervalAfterReopen ensures an existing file is aged from its
+// modification time rather than from the time it is reopened.
+func TestRotatingFileIntervalAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	file := NewRotatingFile(path, WithRotateInterval(time.Minute))
+	defer file.Close()
+
+	_, _ = file.Write([]byte("new\n"))
+	_, _ = file.Write([]byte("still new\n"))
+
+	archives, _ := file.Archives()
+	if len(archives) != 1 {
+		t.Fatalf("Expected 1 archive, got %v", archives)
+	}
+	if content := readFile(t, archives[0]); content != "old\n" {
+		t.Errorf("Unexpected archive content: %q", content)
+	}
+	if content := readFile(t, path); content != "new\nstill new\n" {
+		t.Errorf("Unexpected file content: %q", content)
+	}
+}
+
+// TestRotatingFileMaxArchives ensures only the most recent archives are kept.
+func TestRotatingFileMaxArchives(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file := NewRotatingFile(path, WithMaxArchives(2))
+	defer file.Close()
+
+	for _, line := range []string{"1", "2", "3", "4"} {
+		_, _ = file.Write([]byte(line))
+		if err := file.Rotate(); err != nil {
+			t.Fatalf("Failed to rotate: %v", err)
+		}
+	}
+
+	archives, _ := file.Archives()
+	if len(archives) != 2 {
+		t.Fatalf("Expected 2 archives, got %v", archives)
+	}
+	if readFile(t, archives[0]) != "3" || readFile(t, archives[1]) != "4" {
+		t.Errorf("Expected the most recent archives to be kept, got %v", archives)
+	}
+}
+
+// TestRotatingFileCompression ensures archives are compressed with gzip.
+func TestRotatingFileCompression(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file := NewRotatingFile(path, WithCompression(), WithMaxArchives(1))
+
+	for _, line := range []string{"dropped", "compressed"} {
+		_, _ = file.Write([]byte(line))
+		_ = file.Rotate()
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	archives, _ := file.Archives()
+	if len(archives) != 1 || !strings.HasSuffix(archives[0], ".log.gz") {
+		t.Fatalf("Expected 1 compressed archive, got %v", archives)
+	}
+
+	f, err := os.Open(archives[0])
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Expected a gzip archive: %v", err)
+	}
+	content, _ := io.ReadAll(zr)
+	if string(content) != "compressed" {
+		t.Errorf("Expected the archive to contain %q, got %q", "compressed", content)
+	}
+}
+
+// TestRotatingFileConcurrentWrites ensures concurrent writes are neither lost nor interleaved.
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file := NewRotatingFile(path, WithMaxSize(100))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, _ = file.Write([]byte("0123456789\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	_ = file.Close()
+
+	archives, _ := file.Archives()
+	total := readFile(t, path)
+	for _, archive := range archives {
+		total += readFile(t, archive)
+	}
+	if total != strings.Repeat("0123456789\n", 200) {
+		t.Errorf("Expected 200 intact lines, got %d bytes", len(total))
+	}
+}
+
+// TestLoggerWithLogFile ensures the Logger writes its records to the rotating file.
+func TestLoggerWithLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "events.log")
+	logger := NewLogger(WithLogOutput(io.Discard), WithLogFile(path, WithMaxSize(1<<20)))
+
+	logger.Process(InfoEvent("app", "written to file"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Failed to close logger: %v", err)
+	}
+
+	if content := readFile(t, path); !strings.Contains(content, `msg="written to file"`) {
+		t.Errorf("Expected the record in the log file, got %q", content)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
@@ -0,0 +1,499 @@
+package sirkeji
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSchedulerStopped is returned when scheduling events with a stopped Scheduler.
+	ErrSchedulerStopped = errors.New("scheduler stopped")
+
+	// ErrInvalidInterval is returned when scheduling recurring events with a non-positive interval.
+	ErrInvalidInterval = errors.New("schedule interval must be positive")
+)
+
+// Scheduler publishes events to a Streamer after a delay, at a given time, at a fixed
+// interval or on a cron schedule.
+//
+// Pending schedules may be persisted to a file with WithScheduleFile, so they are
+// restored when the Scheduler is created again after a restart.
+type Scheduler struct {
+	// streamer is the Streamer scheduled events are published to.
+	streamer Streamer
+	// path is the file pending schedules are persisted to, empty if not persisted.
+	path string
+	// clock times the schedules and timestamps the published events, see WithSchedulerClock.
+	clock Clock
+
+	// entries holds the pending schedules by ID.
+	entries map[string]*scheduleEntry
+	// stopped is set once Stop is called.
+	stopped bool
+	// ctx is cancelled when the Scheduler is stopped, releasing the schedules blocked publishing.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg waits for the goroutines running the schedules.
+	wg sync.WaitGroup
+	mu sync.Mutex
+}
+
+// scheduleEntry is a single pending schedule.
+type scheduleEntry struct {
+	id    string
+	event Event
+	// at is the activation time of one-off schedules.
+	at time.Time
+	// every is the interval of recurring schedules.
+	every time.Duration
+	// cron is the schedule of cron schedules.
+	cron *CronSchedule
+	// cancel is closed when the schedule is cancelled or replaced.
+	cancel chan struct{}
+}
+
+// once reports whether the schedule publishes a single event.
+func (e *scheduleEntry) once() bool {
+	return e.every == 0 && e.cron == nil
+}
+
+// next returns the activation following prev.
+func (e *scheduleEntry) next(prev time.Time) time.Time {
+	switch {
+	case e.cron != nil:
+		return e.cron.Next(prev)
+	case e.every > 0:
+		return prev.Add(e.every)
+	default:
+		return e.at
+	}
+}
+
+// occurrence returns the event to publish for an activation at the given time.
+//
+// One-off schedules publish the scheduled event itself, recurring schedules publish a
+// copy with a new ID on every activation. Either way, the event is timestamped with the
+// activation time.
+func (e *scheduleEntry) occurrence(now time.Time) Event {
+	event := e.event
+	if !e.once() {
+		if event.CorrelationID == event.ID {
+			event.CorrelationID = ""
+		}
+		event.ID = ""
+	}
+	event.Time = now
+	return event
+}
+
+// scheduleRecord is the persisted form of a scheduleEntry.
+type scheduleRecord struct {
+	ID    string        `json:"id"`
+	At    time.Time     `json:"at"`
+	Every time.Duration `json:"every,omitempty"`
+	Cron  string        `json:"cron,omitempty"`
+	Event eventEnvelope `json:"event"`
+}
+
+// record returns the persisted form of the entry.
+func (e *scheduleEntry) record() (scheduleRecord, error) {
+	env, err := newEventEnvelope(e.event)
+	if err != nil {
+		return scheduleRecord{}, fmt.Errorf("persist schedule %s: %w", e.id, err)
+	}
+	rec := scheduleRecord{ID: e.id, At: e.at, Every: e.every, Event: env}
+	if e.cron != nil {
+		rec.Cron = e.cron.String()
+	}
+	return rec, nil
+}
+
+// SchedulerOption configures a Scheduler created by NewScheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithScheduleFile persists the pending schedules to the given file.
+//
+// The file is rewritten whenever a schedule is added, cancelled or completed, and
+// the schedules it holds are restored by NewScheduler. Payloads of scheduled events
+// are encoded with the Codec of their EventType, see WithCodec.
+//
+// Example:
+//
+//	scheduler, err := NewScheduler(streamer, WithScheduleFile("data/schedules.json"))
+func WithScheduleFile(path string) SchedulerOption {
+	return func(s *Scheduler) {
+		s.path = path
+	}
+}
+
+// WithSchedulerClock sets the Clock timing the schedules, SystemClock by default.
+//
+// Example:
+//
+//	clock := sirkejitest.NewFakeClock(time.Now())
+//	scheduler, err := NewScheduler(streamer, WithSchedulerClock(clock))
+//	_, _ = scheduler.Every(time.Minute, NewEvent("monitor", HealthCheck, "", nil))
+//	clock.Advance(time.Minute) // publishes the HealthCheck event
+func WithSchedulerClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// ScheduleOption configures a single schedule.
+type ScheduleOption func(*scheduleEntry)
+
+// WithScheduleID sets the ID of a schedule, replacing any pending schedule with the same ID.
+//
+// Use it to schedule recurring events at startup without piling up duplicates of the
+// schedules restored from WithScheduleFile.
+//
+// Example:
+//
+//	scheduler.Cron("0 3 * * *", NewEvent("maintenance", Cleanup, "", nil), WithScheduleID("nightly-cleanup"))
+func WithScheduleID(id string) ScheduleOption {
+	return func(e *scheduleEntry) {
+		e.id = id
+	}
+}
+
+// ScheduleHandle refers to a pending schedule.
+type ScheduleHandle struct {
+	id        string
+	scheduler *Scheduler
+}
+
+// ID returns the ID of the schedule.
+func (h *ScheduleHandle) ID() string {
+	return h.id
+}
+
+// Cancel cancels the schedule, see Scheduler.Cancel.
+func (h *ScheduleHandle) Cancel() bool {
+	return h.scheduler.Cancel(h.id)
+}
+
+// NewScheduler creates a Scheduler publishing to the given Streamer.
+//
+// Parameters:
+//   - streamer: The Streamer scheduled events are published to. Must not be nil.
+//   - opts: Optional SchedulerOptions.
+//
+// Returns:
+//   - A pointer to a new Scheduler, running the schedules restored from WithScheduleFile.
+//   - An error if the streamer is nil or the persisted schedules couldn't be restored.
+//
+// Behavior:
+//   - One-off schedules that became due while the application wasn't running are
+//     published right away.
+//
+// Example:
+//
+//	scheduler, err := NewScheduler(streamer)
+//	if err != nil {
+//	    log.Fatalf("failed to create scheduler: %v", err)
+//	}
+//	defer scheduler.Stop()
+func NewScheduler(streamer Streamer, opts ...SchedulerOption) (*Scheduler, error) {
+	if streamer == nil {
+		return nil, ErrStreamerShouldNotBeNil
+	}
+
+	s := &Scheduler{
+		streamer: streamer,
+		clock:    SystemClock,
+		entries:  make(map[string]*scheduleEntry),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.restore(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// After publishes the event once the delay has passed.
+//
+// Example:
+//
+//	handle, err := scheduler.After(30*time.Second, NewEvent("checkout", CartExpired, cartID, nil))
+func (s *Scheduler) After(delay time.Duration, event Event, opts ...ScheduleOption) (*ScheduleHandle, error) {
+	return s.schedule(&scheduleEntry{event: event, at: s.clock.Now().Add(delay)}, opts)
+}
+
+// At publishes the event at the given time, or right away if the time has passed or is zero.
+//
+// Example:
+//
+//	handle, err := scheduler.At(launch, NewEvent("store", SaleStarted, "", nil))
+func (s *Scheduler) At(t time.Time, event Event, opts ...ScheduleOption) (*ScheduleHandle, error) {
+	return s.schedule(&scheduleEntry{event: event, at: t}, opts)
+}
+
+// Every publishes the event repeatedly, at a fixed interval, until the schedule is cancelled.
+//
+// Returns ErrInvalidInterval if the interval isn't positive.
+//
+// Example:
+//
+//	handle, err := scheduler.Every(time.Minute, NewEvent("monitor", HealthCheck, "", nil))
+func (s *Scheduler) Every(interval time.Duration, event Event, opts ...ScheduleOption) (*ScheduleHandle, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	return s.schedule(&scheduleEntry{event: event, every: interval}, opts)
+}
+
+// Cron publishes the event at every time matching the cron expression, see ParseCron.
+//
+// Returns an error wrapping ErrInvalidCronExpression if the expression is malformed.
+//
+// Example:
+//
+//	handle, err := scheduler.Cron("*/15 9-17 * * 1-5", NewEvent("reports", BuildReport, "", nil))
+func (s *Scheduler) Cron(expr string, event Event, opts ...ScheduleOption) (*ScheduleHandle, error) {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	return s.schedule(&scheduleEntry{event: event, cron: schedule}, opts)
+}
+
+// schedule adds the entry, persists it and starts running it.
+func (s *Scheduler) schedule(e *scheduleEntry, opts []ScheduleOption) (*ScheduleHandle, error) {
+	e.event = stampEvent(e.event)
+	e.cancel = make(chan struct{})
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.id == "" {
+		e.id = newEventID()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return nil, ErrSchedulerStopped
+	}
+
+	replaced, exists := s.entries[e.id]
+	s.entries[e.id] = e
+	if err := s.persist(); err != nil {
+		if exists {
+			s.entries[e.id] = replaced
+		} else {
+			delete(s.entries, e.id)
+		}
+		return nil, err
+	}
+	if exists {
+		close(replaced.cancel)
+	}
+
+	s.start(e)
+	return &ScheduleHandle{id: e.id, scheduler: s}, nil
+}
+
+// start runs the entry in a new goroutine, the caller must hold the lock.
+func (s *Scheduler) start(e *scheduleEntry) {
+	s.wg.Add(1)
+	go s.run(e, s.clock.Now())
+}
+
+// run publishes the events of the entry, scheduled at the given time, until it completes,
+// is cancelled or the Scheduler stops.
+func (s *Scheduler) run(e *scheduleEntry, prev time.Time) {
+	defer s.wg.Done()
+
+	for {
+		next := e.next(prev)
+		if next.IsZero() && !e.once() {
+			log.Printf("[scheduler] schedule %s has no next activation\n", e.id)
+			s.remove(e)
+			return
+		}
+
+		timer := s.clock.NewTimer(next.Sub(s.clock.Now()))
+		select {
+		case <-e.cancel:
+			timer.Stop()
+			return
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		err := s.streamer.PublishContext(s.ctx, e.occurrence(s.clock.Now()))
+		if err != nil && s.ctx.Err() == nil {
+			log.Printf("[scheduler] failed to publish schedule %s: %v\n", e.id, err)
+		}
+		if e.once() {
+			// Keep the schedule if Stop interrupted publishing, so it is published again once restored.
+			if s.ctx.Err() == nil {
+				s.remove(e)
+			}
+			return
+		}
+
+		// Skip the activations missed while publishing, rather than catching up on them.
+		prev = next
+		if now := s.clock.Now(); e.next(prev).Before(now) {
+			prev = now
+		}
+	}
+}
+
+// remove forgets a completed entry, unless it was replaced in the meantime.
+func (s *Scheduler) remove(e *scheduleEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries[e.id] != e {
+		return
+	}
+	delete(s.entries, e.id)
+	if err := s.persist(); err != nil {
+		log.Printf("[scheduler] failed to persist schedules: %v\n", err)
+	}
+}
+
+// Cancel cancels a pending schedule by ID, including schedules restored from WithScheduleFile.
+//
+// Returns:
+//   - true if the schedule was pending, false if it was unknown or already completed.
+//
+// Example:
+//
+//	handle, _ := scheduler.After(time.Minute, reminder)
+//	scheduler.Cancel(handle.ID())
+func (s *Scheduler) Cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, exists := s.entries[id]
+	if !exists {
+		return false
+	}
+	delete(s.entries, id)
+	close(e.cancel)
+
+	if err := s.persist(); err != nil {
+		log.Printf("[scheduler] failed to persist schedules: %v\n", err)
+	}
+	return true
+}
+
+// Pending returns the IDs of the pending schedules, sorted.
+func (s *Scheduler) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stop stops publishing scheduled events and waits for the running schedules to return.
+//
+// Schedules blocked publishing to a full subscriber queue give up. Persisted schedules
+// are kept, so they are restored by the next Scheduler using the same file, including
+// one-off schedules that were being published.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// persist writes the pending schedules to the file, the caller must hold the lock.
+func (s *Scheduler) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	records := make([]scheduleRecord, 0, len(s.entries))
+	for _, e := range s.entries {
+		rec, err := e.record()
+		if err != nil {
+			return err
+		}
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("persist schedules: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("persist schedules: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("persist schedules: %w", err)
+	}
+	return nil
+}
+
+// restore reads the persisted schedules and starts running them.
+func (s *Scheduler) restore() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("restore schedules: %w", err)
+	}
+
+	var records []scheduleRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("restore schedules: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range records {
+		event, err := rec.Event.event()
+		if err != nil {
+			return fmt.Errorf("restore schedule %s: %w", rec.ID, err)
+		}
+		e := &scheduleEntry{id: rec.ID, event: event, at: rec.At, every: rec.Every, cancel: make(chan struct{})}
+		if rec.Cron != "" {
+			if e.cron, err = ParseCron(rec.Cron); err != nil {
+				return fmt.Errorf("restore schedule %s: %w", rec.ID, err)
+			}
+		}
+		s.entries[e.id] = e
+	}
+	for _, e := range s.entries {
+		s.start(e)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package sirkeji
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestScheduler(t *testing.T, streamer Streamer, opts ...SchedulerOption) *Scheduler {
+	t.Helper()
+
+	scheduler, err := NewScheduler(streamer, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(scheduler.Stop)
+	return scheduler
+}
+
+func receiveWithin(t *testing.T, ch chan Event, d time.Duration) Event {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(d):
+		t.Fatal("timed out waiting for a scheduled event")
+		return Event{}
+	}
+}
+
+// TestSchedulerAfter ensures events are published once the delay has passed.
+func TestSchedulerAfter(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	scheduler := newTestScheduler(t, streamer)
+
+	event := InfoEvent("system", "later")
+	start := time.Now()
+	if _, err := scheduler.After(30*time.Millisecond, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := receiveWithin(t, ch, time.Second)
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected the event after 30ms, got it after %v", elapsed)
+	}
+	if received.ID != event.ID || received.Meta != "later" {
+		t.Errorf("expected the scheduled event, got %+v", received)
+	}
+	if len(scheduler.Pending()) != 0 {
+		t.Errorf("expected no pending schedules, got %v", scheduler.Pending())
+	}
+}
+
+// TestSchedulerCancel ensures cancelled schedules are not published.
+func TestSchedulerCancel(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	scheduler := newTestScheduler(t, streamer)
+
+	handle, _ := scheduler.After(20*time.Millisecond, InfoEvent("system", "cancelled"))
+	if !handle.Cancel() {
+		t.Error("expected the pending schedule to be cancelled")
+	}
+	if handle.Cancel() {
+		t.Error("expected cancelling twice to report false")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if len(ch) != 0 {
+		t.Errorf("expected no events, got %d", len(ch))
+	}
+}
+
+// TestSchedulerEvery ensures recurring schedules publish a new event on every activation.
+func TestSchedulerEvery(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	scheduler := newTestScheduler(t, streamer)
+
+	if _, err := scheduler.Every(0, InfoEvent("system", "tick")); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("expected ErrInvalidInterval, got %v", err)
+	}
+
+	handle, _ := scheduler.Every(10*time.Millisecond, InfoEvent("system", "tick"))
+	first := receiveWithin(t, ch, time.Second)
+	second := receiveWithin(t, ch, time.Second)
+	handle.Cancel()
+
+	if first.ID == second.ID {
+		t.Errorf("expected every activation to publish a new event, got ID %s twice", first.ID)
+	}
+	if first.CorrelationID != first.ID {
+		t.Errorf("expected every activation to start a new correlation chain, got %+v", first)
+	}
+}
+
+// TestSchedulerReplace ensures scheduling with an existing ID replaces the pending schedule.
+func TestSchedulerReplace(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	scheduler := newTestScheduler(t, streamer)
+
+	_, _ = scheduler.After(20*time.Millisecond, InfoEvent("system", "first"), WithScheduleID("reminder"))
+	_, _ = scheduler.After(20*time.Millisecond, InfoEvent("system", "second"), WithScheduleID("reminder"))
+
+	if received := receiveWithin(t, ch, time.Second); received.Meta != "second" {
+		t.Errorf("expected the replacing schedule to be published, got %+v", received)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(ch) != 0 {
+		t.Errorf("expected the replaced schedule not to be published, got %d events", len(ch))
+	}
+}
+
+// TestSchedulerStop ensures a stopped Scheduler rejects new schedules.
+func TestSchedulerStop(t *testing.T) {
+	scheduler := newTestScheduler(t, NewStreamer())
+	scheduler.Stop()
+
+	if _, err := scheduler.After(time.Millisecond, InfoEvent("system", "late")); !errors.Is(err, ErrSchedulerStopped) {
+		t.Errorf("expected ErrSchedulerStopped, got %v", err)
+	}
+}
+
+// TestSchedulerPersistence ensures pending schedules survive a restart.
+func TestSchedulerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	order := codecTestOrder{ID: "order-3", Items: []string{"ayran"}, Total: 1.5}
+
+	scheduler := newTestScheduler(t, NewStreamer(), WithScheduleFile(path))
+	_, _ = scheduler.After(time.Hour, InfoEvent("system", "hourly"), WithScheduleID("later"))
+	_, _ = scheduler.Cron("0 0 * * *", InfoEvent("system", "midnight"), WithScheduleID("daily"))
+	_, _ = scheduler.After(30*time.Millisecond, codecTestJSONOrder.New("system", "", order), WithScheduleID("soon"))
+
+	if _, err := scheduler.After(time.Hour, NewEvent("system", codecTestNoCodec, "", 1)); !errors.Is(err, ErrNoPayloadCodec) {
+		t.Errorf("expected ErrNoPayloadCodec, got %v", err)
+	}
+	scheduler.Stop()
+
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	restored := newTestScheduler(t, streamer, WithScheduleFile(path))
+
+	if want := []string{"daily", "later", "soon"}; !reflect.DeepEqual(restored.Pending(), want) {
+		t.Errorf("expected pending schedules %v, got %v", want, restored.Pending())
+	}
+
+	received := receiveWithin(t, ch, time.Second)
+	payload, err := codecTestJSONOrder.Payload(received)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(payload, order) {
+		t.Errorf("expected payload %+v, got %+v", order, payload)
+	}
+
+	restored.Cancel("later")
+	restored.Stop()
+
+	again := newTestScheduler(t, NewStreamer(), WithScheduleFile(path))
+	if want := []string{"daily"}; !reflect.DeepEqual(again.Pending(), want) {
+		t.Errorf("expected pending schedules %v, got %v", want, again.Pending())
+	}
+}
+
+// TestSchedulerAtZeroTime ensures events scheduled at the zero time are published right away.
+func TestSchedulerAtZeroTime(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+	scheduler := newTestScheduler(t, streamer)
+
+	if _, err := scheduler.At(time.Time{}, InfoEvent("system", "now")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received := receiveWithin(t, ch, time.Second); received.Meta != "now" {
+		t.Errorf("expected the scheduled event, got %+v", received)
+	}
+}
+
+// TestSchedulerStopWhilePublishing ensures Stop releases schedules blocked on a full queue
+// and keeps their one-off schedules persisted.
+func TestSchedulerStopWhilePublishing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	streamer := NewStreamer()
+	_, _ = streamer.Subscribe("stuck", WithQueueSize(0))
+	scheduler := newTestScheduler(t, streamer, WithScheduleFile(path))
+
+	_, _ = scheduler.After(0, InfoEvent("system", "blocked"), WithScheduleID("blocked"))
+	time.Sleep(20 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("expected Stop to return while publishing is blocked")
+	}
+
+	// The restored schedule blocks on the stuck subscriber again, rather than completing.
+	restored := newTestScheduler(t, streamer, WithScheduleFile(path))
+	if want := []string{"blocked"}; !reflect.DeepEqual(restored.Pending(), want) {
+		t.Errorf("expected pending schedules %v, got %v", want, restored.Pending())
+	}
+}
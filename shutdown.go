@@ -0,0 +1,154 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+// drainPollInterval is how often GracefulShutdown checks whether subscribers are drained.
+const drainPollInterval = 10 * time.Millisecond
+
+// ErrStreamerStopped is returned when publishing to a Streamer that stopped accepting events.
+var ErrStreamerStopped = errors.New("streamer stopped accepting events")
+
+// ShutdownReport describes the outcome of GracefulShutdown.
+//
+// Fields:
+//   - Unsubscribed: The UIDs of the unsubscribed Subscribers, in the order they were unsubscribed.
+//   - TimedOut: The UIDs of the Subscribers that still had queued or in-flight events at the deadline.
+type ShutdownReport struct {
+	Unsubscribed []string
+	TimedOut     []string
+}
+
+// managerRegistry is implemented by Streamers keeping track of the SubscriptionManagers
+// consuming their subscriptions, like DefaultStreamer, so GracefulShutdown can drain
+// and unsubscribe them.
+type managerRegistry interface {
+	// registerManager records the manager of a subscription, returning the subscription,
+	// or nil if the subscriber isn't subscribed anymore.
+	registerManager(sm *SubscriptionManager) *subscription
+	// managedSubscriptions returns the subscriptions consumed by a manager, in subscription order.
+	managedSubscriptions() []*subscription
+}
+
+// registerManager records the SubscriptionManager consuming the queue of its subscriber.
+//
+// The manager is forgotten along with the subscription when the subscriber is unsubscribed.
+func (s *DefaultStreamer) registerManager(sm *SubscriptionManager) *subscription {
+	s.Lock()
+	defer s.Unlock()
+
+	sub, ok := s.subscribers[sm.subscriber.Uid()]
+	if !ok {
+		return nil
+	}
+	sub.manager = sm
+	return sub
+}
+
+// managedSubscriptions returns the subscriptions consumed by a SubscriptionManager, in subscription order.
+func (s *DefaultStreamer) managedSubscriptions() []*subscription {
+	s.RLock()
+	defer s.RUnlock()
+
+	var subs []*subscription
+	for _, sub := range s.subscribers {
+		if sub.manager != nil {
+			subs = append(subs, sub)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].seq < subs[j].seq
+	})
+	return subs
+}
+
+// GracefulShutdown gracefully stops the streamer and its subscribers.
+//
+// Parameters:
+//   - ctx: Bounds the time spent waiting for subscribers to drain, acting as a hard deadline.
+//   - streamer: The Streamer to shut down.
+//
+// Returns:
+//   - A ShutdownReport listing the unsubscribed Subscribers and those that timed out.
+//
+// Behavior:
+//   - Stops the streamer from accepting new events, if it supports it (e.g. DefaultStreamer).
+//   - Waits until every Subscriber subscribed through a SubscriptionManager has an empty
+//     queue and no in-flight Process calls, or until the context is done. Events are
+//     counted from the moment they are queued, including those held during a replay.
+//   - Only Streamers keeping track of their SubscriptionManagers, like DefaultStreamer,
+//     can be drained; other Streamers are only stopped.
+//   - Unsubscribes the Subscribers in reverse subscription order, so the first subscribed
+//     components, like loggers, see the others leave.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	report := sirkeji.GracefulShutdown(ctx, streamer)
+//	for _, uid := range report.TimedOut {
+//	    log.Printf("%s didn't finish processing in time", uid)
+//	}
+func GracefulShutdown(ctx context.Context, streamer Streamer) *ShutdownReport {
+	if stopper, ok := streamer.(interface{ StopPublishing() }); ok {
+		stopper.StopPublishing()
+	}
+
+	report := &ShutdownReport{}
+	registry, ok := streamer.(managerRegistry)
+	if !ok {
+		return report
+	}
+	subs := registry.managedSubscriptions()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+drain:
+	for waiting := busySubscriptions(subs); len(waiting) > 0; waiting = busySubscriptions(waiting) {
+		select {
+		case <-ctx.Done():
+			for _, sub := range waiting {
+				report.TimedOut = append(report.TimedOut, sub.uid)
+				log.Printf("[%s] did not drain before the shutdown deadline\n", sub.uid)
+			}
+			break drain
+		case <-ticker.C:
+		}
+	}
+
+	for i := len(subs) - 1; i >= 0; i-- {
+		subs[i].manager.Unsubscribe()
+		report.Unsubscribed = append(report.Unsubscribed, subs[i].uid)
+	}
+	return report
+}
+
+// busySubscriptions returns the subscriptions that still have unprocessed events.
+func busySubscriptions(subs []*subscription) []*subscription {
+	var busy []*subscription
+	for _, sub := range subs {
+		if sub.unprocessed.Load() != 0 {
+			busy = append(busy, sub)
+		}
+	}
+	return busy
+}
+
+// track wraps process, marking the events of the subscription as processed. The
+// subscription is nil for Streamers that don't keep track of their managers.
+func track(process ProcessFunc, sub *subscription) ProcessFunc {
+	if sub == nil {
+		return process
+	}
+	return func(event Event) {
+		defer sub.unprocessed.Add(-1)
+		process(event)
+	}
+}
@@ -0,0 +1,188 @@
+package sirkeji
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// orderedSubscriber records its UID in a shared slice when unsubscribed.
+type orderedSubscriber struct {
+	*MockSubscriber
+	mu    *sync.Mutex
+	order *[]string
+}
+
+func (s *orderedSubscriber) Unsubscribed() {
+	s.MockSubscriber.Unsubscribed()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.order = append(*s.order, s.Uid())
+}
+
+// blockingSubscriber blocks in Process until release is closed.
+type blockingSubscriber struct {
+	*MockSubscriber
+	release chan struct{}
+}
+
+func (bs *blockingSubscriber) Process(event Event) {
+	<-bs.release
+	bs.MockSubscriber.Process(event)
+}
+
+// TestGracefulShutdown ensures queued events are processed before subscribers are unsubscribed.
+func TestGracefulShutdown(t *testing.T) {
+	streamer := NewStreamer()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	first := &orderedSubscriber{MockSubscriber: NewMockSubscriber("first"), mu: &mu, order: &order}
+	second := newConcurrencySubscriber("second", 10, 5*time.Millisecond)
+	third := &orderedSubscriber{MockSubscriber: NewMockSubscriber("third"), mu: &mu, order: &order}
+
+	subscribeWith(t, streamer, first)
+	subscribeWith(t, streamer, second, WithSequentialProcessing())
+	subscribeWith(t, streamer, third)
+
+	for i := 0; i < 10; i++ {
+		streamer.Publish(numberedEvent(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report := GracefulShutdown(ctx, streamer)
+
+	if len(report.TimedOut) != 0 {
+		t.Errorf("expected no subscriber to time out, got %v", report.TimedOut)
+	}
+	if len(second.GetProcessedEvents()) != 10 {
+		t.Errorf("expected 10 processed events, got %d", len(second.GetProcessedEvents()))
+	}
+
+	expected := []string{"third", "second", "first"}
+	if !reflect.DeepEqual(report.Unsubscribed, expected) {
+		t.Errorf("expected unsubscribe order %v, got %v", expected, report.Unsubscribed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(order, []string{"third", "first"}) {
+		t.Errorf("expected Unsubscribed to be called in order [third first], got %v", order)
+	}
+	if len(streamer.managedSubscriptions()) != 0 {
+		t.Error("expected every manager to be unregistered")
+	}
+}
+
+// TestGracefulShutdownTimeout ensures subscribers that don't drain before the deadline are reported.
+func TestGracefulShutdownTimeout(t *testing.T) {
+	streamer := NewStreamer()
+
+	fast := NewMockSubscriber("fast")
+	slow := &blockingSubscriber{MockSubscriber: NewMockSubscriber("slow"), release: make(chan struct{})}
+	defer close(slow.release)
+
+	subscribeWith(t, streamer, fast)
+	subscribeWith(t, streamer, slow)
+
+	streamer.Publish(InfoEvent("system", "hello"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	report := GracefulShutdown(ctx, streamer)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the shutdown to respect the deadline, took %v", elapsed)
+	}
+	if !reflect.DeepEqual(report.TimedOut, []string{"slow"}) {
+		t.Errorf("expected [slow] to time out, got %v", report.TimedOut)
+	}
+	if !reflect.DeepEqual(report.Unsubscribed, []string{"slow", "fast"}) {
+		t.Errorf("expected [slow fast] to be unsubscribed, got %v", report.Unsubscribed)
+	}
+}
+
+// TestStopPublishing ensures a stopped streamer rejects new events.
+func TestStopPublishing(t *testing.T) {
+	streamer := NewStreamer()
+	ch, _ := streamer.Subscribe("user123")
+
+	streamer.StopPublishing()
+
+	if err := streamer.PublishContext(context.Background(), InfoEvent("system", "hello")); !errors.Is(err, ErrStreamerStopped) {
+		t.Errorf("expected ErrStreamerStopped, got %v", err)
+	}
+	streamer.Publish(InfoEvent("system", "hello"))
+
+	if len(ch) != 0 {
+		t.Errorf("expected no events to be delivered, got %d", len(ch))
+	}
+}
+
+// TestGracefulShutdownReplay ensures replayed and held events are drained before unsubscribing.
+func TestGracefulShutdownReplay(t *testing.T) {
+	eventLog, err := OpenEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+
+	streamer := NewStreamer(WithEventLog(eventLog))
+	for i := 0; i < 5; i++ {
+		streamer.Publish(numberedEvent(i))
+	}
+
+	subscriber := newConcurrencySubscriber("replayer", 10, time.Millisecond)
+	subscribeWith(t, streamer, subscriber, WithSequentialProcessing(), WithSubscribeOptions(FromBeginning()))
+	for i := 5; i < 10; i++ {
+		streamer.Publish(numberedEvent(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if report := GracefulShutdown(ctx, streamer); len(report.TimedOut) != 0 {
+		t.Errorf("expected no subscriber to time out, got %v", report.TimedOut)
+	}
+	if processed := len(subscriber.GetProcessedEvents()); processed != 10 {
+		t.Errorf("expected the 10 replayed and live events to be processed, got %d", processed)
+	}
+}
+
+// TestWaitForTerminationDeadline ensures a subscriber with a full queue can't hold the
+// Shutdown event past the delay.
+func TestWaitForTerminationDeadline(t *testing.T) {
+	streamer := NewStreamer()
+	stuck := &blockingSubscriber{MockSubscriber: NewMockSubscriber("stuck"), release: make(chan struct{})}
+	defer close(stuck.release)
+	subscribeWith(t, streamer, stuck, WithSequentialProcessing(), WithSubscribeOptions(WithQueueSize(1)))
+
+	streamer.Publish(InfoEvent("system", "processing"))
+	streamer.Publish(InfoEvent("system", "queued"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reports := make(chan *ShutdownReport, 1)
+	go func() {
+		reports <- WaitForTermination(ctx, streamer, 50*time.Millisecond)
+	}()
+
+	select {
+	case report := <-reports:
+		if !reflect.DeepEqual(report.TimedOut, []string{"stuck"}) {
+			t.Errorf("expected [stuck] to time out, got %v", report.TimedOut)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the shutdown to complete once the delay passed")
+	}
+}
@@ -17,6 +17,7 @@ import (
 // Parameters:
 //   - streamer: The Streamer instance to which the Subscriber will be connected.
 //   - subscriber: The Subscriber instance that will receive events from the Streamer.
+//   - opts: Optional ManagerOptions, e.g. WithSubscribeOptions to configure the subscriber's queue.
 //
 // Panics:
 //   - If the SubscriptionManager cannot be created (e.g., due to invalid arguments).
@@ -27,8 +28,10 @@ import (
 //	subscriber := &MySubscriber{}
 //	streamer := sirkeji.NewStreamer()
 //	sirkeji.Subscribe(streamer, subscriber)
-func Subscribe(streamer Streamer, subscriber Subscriber) {
-	manager, err := NewSubscriptionManager(streamer, subscriber)
+//	sirkeji.Subscribe(streamer, &SlowSubscriber{},
+//	    sirkeji.WithSubscribeOptions(sirkeji.WithOverflowPolicy(sirkeji.OverflowDropOldest)))
+func Subscribe(streamer Streamer, subscriber Subscriber, opts ...ManagerOption) {
+	manager, err := NewSubscriptionManager(streamer, subscriber, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -65,36 +68,81 @@ func Unsubscribe(streamer Streamer, subscriber Subscriber) {
 	manager.Unsubscribe()
 }
 
-// WaitForTermination waits for OS termination signals and publishes a Shutdown event.
+// WaitForTermination waits for OS termination signals, publishes a Shutdown event and
+// gracefully shuts the streamer down.
 //
 // Parameters:
 //   - ctx: Global context.Context
 //   - streamer: The Streamer instance to publish the Shutdown event.
-//   - delay: Custom termination delay for application to close
+//   - delay: The hard deadline for subscribers to finish processing their events.
+//   - opts: Optional TerminationOptions, e.g. WithTerminationClock.
+//
+// Returns:
+//   - A ShutdownReport listing the unsubscribed Subscribers and those that timed out.
 //
 // Behavior:
-//   - Waits for SIGINT or SIGTERM signals.
-//   - Publishes a Shutdown event with the publisher set to "main".
-//   - Blocks execution until the termination signal is received and the event is published.
+//   - Waits for SIGINT or SIGTERM signals, or for ctx to be done.
+//   - Starts the delay, then publishes a Shutdown event with the publisher set to "main",
+//     giving up on subscribers whose queues are still full at the deadline.
+//   - Stops accepting new events, waits for the subscribers to drain their queues and
+//     unsubscribes them, see GracefulShutdown. Returns as soon as every subscriber is
+//     drained, or once the delay has passed on the Clock.
 //
 // Example:
 //
-//	sirkeji.WaitForTermination(streamer)
-func WaitForTermination(ctx context.Context, streamer Streamer, delay time.Duration) {
+//	report := sirkeji.WaitForTermination(ctx, streamer, 5*time.Second)
+//	if len(report.TimedOut) > 0 {
+//	    log.Printf("subscribers cut off: %v", report.TimedOut)
+//	}
+func WaitForTermination(ctx context.Context, streamer Streamer, delay time.Duration, opts ...TerminationOption) *ShutdownReport {
+	cfg := terminationConfig{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
 	// Wait for termination signal
 	<-ctx.Done()
 
-	// Publish a Shutdown event
-	streamer.Publish(Event{
-		Publisher: "main",
-		Type:      Shutdown,
-		Meta:      "Application is shutting down",
-		Payload:   nil,
-	})
+	// Bound the shutdown by the delay, publishing the Shutdown event included
+	shutdownCtx, cancelShutdown := context.WithCancel(context.Background())
+	defer cancelShutdown()
+
+	deadline := cfg.clock.NewTimer(delay)
+	defer deadline.Stop()
+	go func() {
+		select {
+		case <-deadline.C():
+			cancelShutdown()
+		case <-shutdownCtx.Done():
+		}
+	}()
+
+	// Publish a Shutdown event, subscribers with full queues can't hold it past the deadline
+	_ = streamer.PublishContext(shutdownCtx, NewEvent("main", Shutdown, "Application is shutting down", nil))
+
+	// Allow subscribers to process the queued events
+	return GracefulShutdown(shutdownCtx, streamer)
+}
 
-	// Allow subscribers time to process the Shutdown event
-	time.Sleep(delay)
+// TerminationOption configures WaitForTermination.
+type TerminationOption func(*terminationConfig)
+
+// terminationConfig holds the settings collected from TerminationOptions.
+type terminationConfig struct {
+	clock Clock
+}
+
+// WithTerminationClock sets the Clock measuring the termination delay, SystemClock by default.
+//
+// Example:
+//
+//	clock := sirkejitest.NewFakeClock(time.Now())
+//	go sirkeji.WaitForTermination(ctx, streamer, 5*time.Second, sirkeji.WithTerminationClock(clock))
+func WithTerminationClock(clock Clock) TerminationOption {
+	return func(cfg *terminationConfig) {
+		cfg.clock = clock
+	}
 }
@@ -0,0 +1,148 @@
+package sirkejitest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+)
+
+// AssertEventually waits until an event of the given type, matching the predicate, is
+// recorded by the Source, failing the test with t.Fatalf if none is within the timeout.
+//
+// Parameters:
+//   - t: The test.
+//   - source: The Recorder or SyncStreamer the events are published on.
+//   - eventType: The type of the expected event, it may be a wildcard pattern.
+//   - match: An optional predicate the event must satisfy, nil accepts any event of the type.
+//   - timeout: How long to wait for the event.
+//
+// Returns:
+//   - The first recorded event of the type matching the predicate.
+//
+// Example:
+//
+//	event := sirkejitest.AssertEventually(t, recorder, events.SquaredNumber.Type(),
+//	    func(e sirkeji.Event) bool { return e.Payload == 9 }, time.Second)
+func AssertEventually(t testing.TB, source Source, eventType sirkeji.EventType, match func(sirkeji.Event) bool, timeout time.Duration) sirkeji.Event {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		events, changed := source.snapshot()
+		for _, event := range events {
+			if sirkeji.MatchEventType(eventType, event.Type) && (match == nil || match(event)) {
+				return event
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			t.Fatalf("no matching %s event received within %v, got:\n%s", eventType, timeout, formatEvents(source.Events()))
+			return sirkeji.Event{}
+		}
+	}
+}
+
+// AssertEvents waits until the Source recorded as many events as expected, or the
+// timeout passed, and checks it recorded exactly the expected events in order.
+//
+// Events are compared by Publisher, Type, Meta and Payload, the other fields are
+// assigned when publishing and ignored. The test is failed with t.Errorf otherwise.
+//
+// Returns:
+//   - true if the expected events were recorded.
+//
+// Example:
+//
+//	sirkejitest.AssertEvents(t, streamer, 0,
+//	    sirkeji.NewEvent("test", events.Number.Type(), "", 3),
+//	    sirkeji.NewEvent("squarer", events.SquaredNumber.Type(), "9", 9))
+func AssertEvents(t testing.TB, source Source, timeout time.Duration, expected ...sirkeji.Event) bool {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	events, changed := source.snapshot()
+wait:
+	for len(events) < len(expected) {
+		select {
+		case <-changed:
+			events, changed = source.snapshot()
+		case <-timer.C:
+			break wait
+		}
+	}
+
+	if slices.EqualFunc(expected, events, sameEvent) {
+		return true
+	}
+
+	t.Errorf("unexpected events, expected:\n%s\ngot:\n%s", formatEvents(expected), formatEvents(events))
+	return false
+}
+
+// AssertNoEvents waits for the given duration and checks the Source recorded no event
+// of the given type, failing the test with t.Errorf otherwise.
+//
+// Returns:
+//   - true if no event of the type was recorded.
+//
+// Example:
+//
+//	streamer.Publish(sirkeji.NewEvent("test", events.Number.Type(), "", 3))
+//	sirkejitest.AssertNoEvents(t, recorder, sirkeji.Error, 100*time.Millisecond)
+func AssertNoEvents(t testing.TB, source Source, eventType sirkeji.EventType, within time.Duration) bool {
+	t.Helper()
+
+	timer := time.NewTimer(within)
+	defer timer.Stop()
+
+	for {
+		events, changed := source.snapshot()
+		for _, event := range events {
+			if sirkeji.MatchEventType(eventType, event.Type) {
+				t.Errorf("unexpected %s event received: %s", eventType, formatEvent(event))
+				return false
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// sameEvent reports whether the events have the same Publisher, Type, Meta and Payload.
+func sameEvent(expected, actual sirkeji.Event) bool {
+	return expected.Publisher == actual.Publisher &&
+		expected.Type == actual.Type &&
+		expected.Meta == actual.Meta &&
+		reflect.DeepEqual(expected.Payload, actual.Payload)
+}
+
+func formatEvents(events []sirkeji.Event) string {
+	if len(events) == 0 {
+		return "\t(no events)"
+	}
+
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = fmt.Sprintf("\t%d: %s", i, formatEvent(event))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatEvent(event sirkeji.Event) string {
+	return fmt.Sprintf("[%s] *%s*, m: %q | pl: %#v", event.Publisher, event.Type, event.Meta, event.Payload)
+}
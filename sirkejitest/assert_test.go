@@ -0,0 +1,96 @@
+package sirkejitest_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+	"github.com/thisiscetin/sirkeji/sirkejitest"
+)
+
+// fakeT records the failures reported by the assertions.
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+}
+
+// TestAssertEventually ensures AssertEventually waits for a matching event.
+func TestAssertEventually(t *testing.T) {
+	recorder := sirkejitest.NewRecorder()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		recorder.Publish(sirkeji.InfoEvent("test", "ignored"))
+		recorder.Publish(sirkeji.InfoEvent("test", "expected"))
+	}()
+
+	event := sirkejitest.AssertEventually(t, recorder, sirkeji.Info, func(e sirkeji.Event) bool {
+		return e.Meta == "expected"
+	}, time.Second)
+	if event.Meta != "expected" {
+		t.Errorf("Expected the matching event, got %+v", event)
+	}
+
+	ft := &fakeT{}
+	sirkejitest.AssertEventually(ft, recorder, sirkeji.Error, nil, 10*time.Millisecond)
+	if len(ft.failures) != 1 {
+		t.Errorf("Expected a failure without matching event, got %v", ft.failures)
+	}
+}
+
+// TestAssertEvents ensures AssertEvents compares the recorded events in order.
+func TestAssertEvents(t *testing.T) {
+	streamer := sirkejitest.NewSyncStreamer()
+	streamer.Publish(sirkeji.InfoEvent("test", "first"))
+	streamer.Publish(sirkeji.ErrorEvent("test", "second"))
+
+	tests := []struct {
+		name     string
+		expected []sirkeji.Event
+		ok       bool
+	}{
+		{"same events", []sirkeji.Event{sirkeji.InfoEvent("test", "first"), sirkeji.ErrorEvent("test", "second")}, true},
+		{"wrong order", []sirkeji.Event{sirkeji.ErrorEvent("test", "second"), sirkeji.InfoEvent("test", "first")}, false},
+		{"missing event", []sirkeji.Event{sirkeji.InfoEvent("test", "first")}, false},
+		{"extra event", []sirkeji.Event{sirkeji.InfoEvent("test", "first"), sirkeji.ErrorEvent("test", "second"), sirkeji.InfoEvent("test", "third")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			if ok := sirkejitest.AssertEvents(ft, streamer, 10*time.Millisecond, tt.expected...); ok != tt.ok {
+				t.Errorf("Expected AssertEvents to return %v, failures: %v", tt.ok, ft.failures)
+			}
+		})
+	}
+}
+
+// TestAssertNoEvents ensures AssertNoEvents fails on events of the type published in time.
+func TestAssertNoEvents(t *testing.T) {
+	recorder := sirkejitest.NewRecorder()
+	recorder.Publish(sirkeji.InfoEvent("test", "allowed"))
+
+	if !sirkejitest.AssertNoEvents(t, recorder, sirkeji.Error, 20*time.Millisecond) {
+		t.Error("Expected no Error event")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		recorder.Publish(sirkeji.ErrorEvent("test", "failed"))
+	}()
+
+	ft := &fakeT{}
+	if sirkejitest.AssertNoEvents(ft, recorder, sirkeji.Error, time.Second) || len(ft.failures) != 1 {
+		t.Errorf("Expected a failure for the Error event, got %v", ft.failures)
+	}
+}
@@ -0,0 +1,255 @@
+package sirkejitest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+)
+
+// FakeClock is a sirkeji.Clock whose time only moves when advanced manually, firing the
+// timers, tickers and sleeps that became due on the way, in time order.
+//
+// It makes time-driven components deterministic and instant to test: schedule with a
+// Scheduler created with sirkeji.WithSchedulerClock, subscribe ClockedSubscribers with
+// sirkeji.WithClock, then Advance the clock instead of sleeping.
+type FakeClock struct {
+	now time.Time
+	// waiters holds the active timers, tickers and sleeps.
+	waiters []*fakeWaiter
+	// seq orders the waiters due at the same time by creation.
+	seq uint64
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+// fakeWaiter is a timer or ticker of a FakeClock.
+type fakeWaiter struct {
+	clock  *FakeClock
+	at     time.Time
+	period time.Duration
+	seq    uint64
+	active bool
+	c      chan time.Time
+}
+
+// NewFakeClock creates a FakeClock set to the given time.
+//
+// Example:
+//
+//	clock := sirkejitest.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Since returns the time elapsed on the clock since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns a channel receiving the time once the clock advanced by the duration.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep blocks until the clock advanced by the duration.
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.After(d)
+}
+
+// NewTimer creates a Timer firing once the clock advanced by the duration.
+// Timers with a non-positive duration fire immediately.
+func (c *FakeClock) NewTimer(d time.Duration) sirkeji.Timer {
+	return c.add(d, 0)
+}
+
+// NewTicker creates a Ticker firing every time the clock advanced by the period.
+// Like time.NewTicker, it panics if the period isn't positive.
+func (c *FakeClock) NewTicker(d time.Duration) sirkeji.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+	return fakeTicker{c.add(d, d)}
+}
+
+// add creates an active waiter due after d.
+func (c *FakeClock) add(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{clock: c, period: period, c: make(chan time.Time, 1)}
+	c.schedule(w, d)
+	return w
+}
+
+// schedule activates the waiter after d, the caller must hold the lock.
+func (c *FakeClock) schedule(w *fakeWaiter, d time.Duration) {
+	c.seq++
+	w.at, w.seq = c.now.Add(d), c.seq
+	if d <= 0 && w.period == 0 {
+		c.unschedule(w)
+		w.fire(c.now)
+		return
+	}
+	if !w.active {
+		w.active = true
+		c.waiters = append(c.waiters, w)
+	}
+	c.cond.Broadcast()
+}
+
+// unschedule deactivates the waiter, the caller must hold the lock.
+func (c *FakeClock) unschedule(w *fakeWaiter) bool {
+	if !w.active {
+		return false
+	}
+	w.active = false
+	for i, waiter := range c.waiters {
+		if waiter == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	c.cond.Broadcast()
+	return true
+}
+
+// Advance moves the clock forward by the duration, firing the timers, tickers and
+// sleeps that became due, in time order. The clock reads the time they were due at
+// while they fire.
+//
+// Components receive the time from their timers asynchronously, use BlockUntil to
+// wait for them to be waiting on the clock before advancing it further.
+//
+// Example:
+//
+//	_, _ = scheduler.Every(2*time.Second, sirkeji.NewEvent("main", events.GenerateNumber, "", nil))
+//	clock.Advance(2 * time.Second)
+//	sirkejitest.AssertEventually(t, recorder, events.Number.Type(), nil, time.Second)
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	for {
+		sort.Slice(c.waiters, func(i, j int) bool {
+			if c.waiters[i].at.Equal(c.waiters[j].at) {
+				return c.waiters[i].seq < c.waiters[j].seq
+			}
+			return c.waiters[i].at.Before(c.waiters[j].at)
+		})
+		if len(c.waiters) == 0 || c.waiters[0].at.After(target) {
+			break
+		}
+
+		w := c.waiters[0]
+		if w.at.After(c.now) {
+			c.now = w.at
+		}
+		w.fire(c.now)
+		if w.period > 0 {
+			c.seq++
+			w.at, w.seq = w.at.Add(w.period), c.seq
+		} else {
+			c.unschedule(w)
+		}
+	}
+	if target.After(c.now) {
+		c.now = target
+	}
+}
+
+// Set moves the clock forward to the given time, see Advance. Earlier times are ignored.
+func (c *FakeClock) Set(t time.Time) {
+	c.Advance(t.Sub(c.Now()))
+}
+
+// Waiters returns the number of active timers, tickers and sleeps.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n timers, tickers or sleeps are active on the clock.
+//
+// Example:
+//
+//	go worker.Run() // sleeps on the clock between runs
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Minute)
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// fire sends the time on the channel of the waiter, dropping it if the previous
+// time wasn't received yet, like time.Ticker does.
+func (w *fakeWaiter) fire(now time.Time) {
+	select {
+	case w.c <- now:
+	default:
+	}
+}
+
+// C implements sirkeji.Timer.
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+// Stop implements sirkeji.Timer.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	return w.clock.unschedule(w)
+}
+
+// Reset implements sirkeji.Timer.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	active := w.active
+	w.clock.schedule(w, d)
+	return active
+}
+
+// fakeTicker implements sirkeji.Ticker with a periodic fakeWaiter.
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+// Stop implements sirkeji.Ticker.
+func (t fakeTicker) Stop() {
+	t.fakeWaiter.Stop()
+}
+
+// Reset implements sirkeji.Ticker.
+func (t fakeTicker) Reset(d time.Duration) {
+	t.fakeWaiter.clock.mu.Lock()
+	defer t.fakeWaiter.clock.mu.Unlock()
+
+	t.period = d
+	t.clock.schedule(t.fakeWaiter, d)
+}
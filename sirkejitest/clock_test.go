@@ -0,0 +1,189 @@
+package sirkejitest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+	"github.com/thisiscetin/sirkeji/example/numbers/events"
+	"github.com/thisiscetin/sirkeji/example/numbers/number"
+	"github.com/thisiscetin/sirkeji/example/numbers/number_count"
+	"github.com/thisiscetin/sirkeji/sirkejitest"
+)
+
+var epoch = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// received reports whether a time was sent on the channel.
+func received(c <-chan time.Time) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+// TestFakeClockTimers ensures timers and tickers only fire when the clock is advanced.
+func TestFakeClockTimers(t *testing.T) {
+	clock := sirkejitest.NewFakeClock(epoch)
+	timer := clock.NewTimer(time.Second)
+	ticker := clock.NewTicker(400 * time.Millisecond)
+	stopped := clock.NewTimer(time.Second)
+	stopped.Stop()
+
+	if clock.Waiters() != 2 {
+		t.Errorf("Expected 2 waiters, got %d", clock.Waiters())
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	if received(timer.C()) {
+		t.Error("Expected the timer not to fire before its time")
+	}
+	if !received(ticker.C()) {
+		t.Error("Expected the ticker to tick")
+	}
+
+	clock.Advance(time.Millisecond)
+	if !received(timer.C()) || received(stopped.C()) {
+		t.Error("Expected only the active timer to fire")
+	}
+	if !clock.Now().Equal(epoch.Add(time.Second)) {
+		t.Errorf("Expected the clock to read %v, got %v", epoch.Add(time.Second), clock.Now())
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	if received(ticker.C()) {
+		t.Error("Expected the stopped ticker not to tick")
+	}
+}
+
+// TestFakeClockSleep ensures sleeping goroutines are woken by Advance.
+func TestFakeClockSleep(t *testing.T) {
+	clock := sirkejitest.NewFakeClock(epoch)
+	woken := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Minute)
+		woken <- clock.Now()
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	select {
+	case now := <-woken:
+		if !now.Equal(epoch.Add(time.Minute)) {
+			t.Errorf("Expected to wake up at %v, got %v", epoch.Add(time.Minute), now)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the sleeping goroutine to wake up")
+	}
+}
+
+// TestFakeClockScheduler ensures scheduled event flows run without waiting for real time.
+func TestFakeClockScheduler(t *testing.T) {
+	clock := sirkejitest.NewFakeClock(epoch)
+	recorder := sirkejitest.NewRecorder()
+	sirkeji.Subscribe(recorder, number.NewPublisher("number-publisher", recorder.Publish))
+
+	scheduler, err := sirkeji.NewScheduler(recorder, sirkeji.WithSchedulerClock(clock))
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+	_, _ = scheduler.Every(2*time.Second, sirkeji.NewEvent("main", events.GenerateNumber, "", nil))
+
+	for i := 1; i <= 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(2 * time.Second)
+		sirkejitest.AssertEventually(t, recorder, events.Number.Type(), func(e sirkeji.Event) bool {
+			return len(recorder.EventsOfType(events.Number.Type())) == i
+		}, time.Second)
+	}
+
+	generated := recorder.EventsOfType(events.GenerateNumber)
+	for i, event := range generated {
+		if expected := epoch.Add(time.Duration(i+1) * 2 * time.Second); !event.Time.Equal(expected) {
+			t.Errorf("Expected occurrence %d at %v, got %v", i, expected, event.Time)
+		}
+	}
+}
+
+// TestFakeClockSubscriber ensures ClockedSubscribers tell the time with the fake clock.
+func TestFakeClockSubscriber(t *testing.T) {
+	clock := sirkejitest.NewFakeClock(epoch)
+	streamer := sirkejitest.NewSyncStreamer()
+	counter := number_count.NewPublisher("number-count", streamer.Publish)
+	counter.SetClock(clock)
+	_ = streamer.Subscribe(counter)
+
+	streamer.Publish(events.Number.New("test", "", 1))
+	streamer.Publish(events.Number.New("test", "", 2))
+
+	replayed := sirkeji.NewEvent("main", events.ReportNumberCount, "", nil)
+	replayed.Time = epoch.Add(-time.Hour)
+	streamer.Publish(replayed)
+
+	live := sirkeji.NewEvent("main", events.ReportNumberCount, "", nil)
+	live.Time = clock.Now()
+	streamer.Publish(live)
+
+	updates := streamer.EventsOfType(events.NumberCountUpdate.Type())
+	if len(updates) != 1 || updates[0].Payload != 2 {
+		t.Errorf("Expected a single count update of 2, got %+v", updates)
+	}
+}
+
+// TestFakeClockTermination ensures the termination delay is measured on the clock.
+func TestFakeClockTermination(t *testing.T) {
+	clock := sirkejitest.NewFakeClock(epoch)
+	recorder := sirkejitest.NewRecorder()
+
+	release := make(chan struct{})
+	defer close(release)
+	sirkeji.Subscribe(recorder, &blocking{uid: "blocking", release: release})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reports := make(chan *sirkeji.ShutdownReport)
+	go func() {
+		reports <- sirkeji.WaitForTermination(ctx, recorder, 5*time.Second, sirkeji.WithTerminationClock(clock))
+	}()
+
+	clock.BlockUntil(1)
+	select {
+	case <-reports:
+		t.Fatal("Expected the shutdown to wait for the blocked subscriber")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(5 * time.Second)
+	select {
+	case report := <-reports:
+		if len(report.TimedOut) != 1 || report.TimedOut[0] != "blocking" {
+			t.Errorf("Expected the blocked subscriber to time out, got %+v", report)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the shutdown to complete once the delay passed on the clock")
+	}
+}
+
+// blocking is a Subscriber whose Process blocks until released.
+type blocking struct {
+	uid     string
+	release chan struct{}
+}
+
+func (b *blocking) Uid() string {
+	return b.uid
+}
+
+func (b *blocking) Process(event sirkeji.Event) {
+	<-b.release
+}
+
+func (b *blocking) Subscribed() {}
+
+func (b *blocking) Unsubscribed() {}
@@ -0,0 +1,114 @@
+// Package sirkejitest provides streamers recording the published events and assertions
+// on them, for testing Subscribers and event flows built on sirkeji.
+package sirkejitest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/thisiscetin/sirkeji"
+)
+
+// Source is a record of published events, which the assertions of this package inspect.
+// It is implemented by Recorder and SyncStreamer.
+type Source interface {
+	// Events returns the recorded events, in publishing order.
+	Events() []sirkeji.Event
+
+	// snapshot returns the recorded events and a channel closed on the next recorded event.
+	snapshot() ([]sirkeji.Event, <-chan struct{})
+}
+
+// recording stores the published events, notifying waiting assertions of new ones.
+type recording struct {
+	events  []sirkeji.Event
+	changed chan struct{}
+	mu      sync.Mutex
+}
+
+func (r *recording) record(event sirkeji.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+	if r.changed != nil {
+		close(r.changed)
+		r.changed = nil
+	}
+}
+
+// Events returns the recorded events, in publishing order.
+func (r *recording) Events() []sirkeji.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]sirkeji.Event(nil), r.events...)
+}
+
+// EventsOfType returns the recorded events matching the EventType, which may be a
+// wildcard pattern, see sirkeji.MatchEventType.
+func (r *recording) EventsOfType(eventType sirkeji.EventType) []sirkeji.Event {
+	var matched []sirkeji.Event
+	for _, event := range r.Events() {
+		if sirkeji.MatchEventType(eventType, event.Type) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// Reset forgets the recorded events.
+func (r *recording) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+func (r *recording) snapshot() ([]sirkeji.Event, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return append([]sirkeji.Event(nil), r.events...), r.changed
+}
+
+// Recorder is a sirkeji.DefaultStreamer recording every event published on it.
+//
+// Subscribers are connected to it like to any Streamer, and receive the events
+// asynchronously through their SubscriptionManagers.
+type Recorder struct {
+	*sirkeji.DefaultStreamer
+	recording
+}
+
+// NewRecorder creates a Recorder.
+//
+// Parameters:
+//   - opts: Optional StreamerOptions of the underlying DefaultStreamer.
+//
+// Behavior:
+//   - Events are recorded once stamped, after the publish middlewares given in opts
+//     accepted them and before they are routed to the subscribers.
+//
+// Example:
+//
+//	recorder := sirkejitest.NewRecorder()
+//	sirkeji.Subscribe(recorder, squared_number.NewPublisher("squarer", recorder.Publish))
+//
+//	recorder.Publish(sirkeji.NewEvent("test", events.Number.Type(), "", 3))
+//	sirkejitest.AssertEventually(t, recorder, events.SquaredNumber.Type(), nil, time.Second)
+func NewRecorder(opts ...sirkeji.StreamerOption) *Recorder {
+	r := &Recorder{}
+	record := func(next sirkeji.PublishFunc) sirkeji.PublishFunc {
+		return func(ctx context.Context, event sirkeji.Event) error {
+			r.record(event)
+			return next(ctx, event)
+		}
+	}
+	opts = append(opts[:len(opts):len(opts)], sirkeji.WithPublishMiddleware(record))
+	r.DefaultStreamer = sirkeji.NewStreamer(opts...)
+	return r
+}
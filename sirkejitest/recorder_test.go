@@ -0,0 +1,54 @@
+package sirkejitest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+	"github.com/thisiscetin/sirkeji/example/numbers/events"
+	"github.com/thisiscetin/sirkeji/example/numbers/squared_number"
+	"github.com/thisiscetin/sirkeji/sirkejitest"
+)
+
+// TestRecorderRecordsPublishedEvents ensures the Recorder records the events published by
+// tests and subscribers, stamped and in order.
+func TestRecorderRecordsPublishedEvents(t *testing.T) {
+	recorder := sirkejitest.NewRecorder()
+	sirkeji.Subscribe(recorder, squared_number.NewPublisher("squarer", recorder.Publish))
+
+	recorder.Publish(sirkeji.Event{Publisher: "test", Type: events.Number.Type(), Payload: 4})
+
+	squared := sirkejitest.AssertEventually(t, recorder, events.SquaredNumber.Type(), nil, time.Second)
+	if squared.Payload != 16 {
+		t.Errorf("Expected the squared number 16, got %v", squared.Payload)
+	}
+
+	recorded := recorder.Events()
+	if len(recorded) != 2 {
+		t.Fatalf("Expected 2 recorded events, got %d", len(recorded))
+	}
+	if recorded[0].ID == "" || recorded[0].Time.IsZero() {
+		t.Errorf("Expected recorded events to be stamped, got %+v", recorded[0])
+	}
+	if squared.CausationID != recorded[0].ID {
+		t.Errorf("Expected the squared number to be caused by %s, got %s", recorded[0].ID, squared.CausationID)
+	}
+}
+
+// TestRecorderEventsOfTypeAndReset ensures recorded events can be filtered and forgotten.
+func TestRecorderEventsOfTypeAndReset(t *testing.T) {
+	recorder := sirkejitest.NewRecorder()
+
+	recorder.Publish(sirkeji.InfoEvent("test", "first"))
+	recorder.Publish(sirkeji.ErrorEvent("test", "failed"))
+	recorder.Publish(sirkeji.InfoEvent("test", "second"))
+
+	if infos := recorder.EventsOfType(sirkeji.Info); len(infos) != 2 {
+		t.Errorf("Expected 2 Info events, got %d", len(infos))
+	}
+
+	recorder.Reset()
+	if recorded := recorder.Events(); len(recorded) != 0 {
+		t.Errorf("Expected no events after Reset, got %d", len(recorded))
+	}
+}
@@ -0,0 +1,161 @@
+package sirkejitest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/thisiscetin/sirkeji"
+)
+
+// SyncStreamer delivers events by calling the Process method of its Subscribers inline,
+// so an event flow has completed by the time Publish returns.
+//
+// It isn't a sirkeji.Streamer: Subscribers are connected to it directly rather than
+// through SubscriptionManagers. Events published while an event is being processed,
+// e.g. events derived by a Subscriber, are queued and delivered once it is processed,
+// in publishing order, before the outermost Publish returns.
+type SyncStreamer struct {
+	recording
+
+	subscribers []sirkeji.Subscriber
+	queue       []sirkeji.Event
+	dispatching bool
+	dispatchMu  sync.Mutex
+}
+
+// NewSyncStreamer creates a SyncStreamer without Subscribers.
+//
+// Example:
+//
+//	streamer := sirkejitest.NewSyncStreamer()
+//	_ = streamer.Subscribe(squared_number.NewPublisher("squarer", streamer.Publish))
+//
+//	streamer.Publish(sirkeji.NewEvent("test", events.Number.Type(), "", 3))
+//	sirkejitest.AssertEvents(t, streamer, 0,
+//	    sirkeji.NewEvent("test", events.Number.Type(), "", 3),
+//	    sirkeji.NewEvent("squarer", events.SquaredNumber.Type(), "9", 9))
+func NewSyncStreamer() *SyncStreamer {
+	return &SyncStreamer{}
+}
+
+// Subscribe connects the Subscriber and calls its Subscribed method.
+//
+// Subscribers implementing sirkeji.FilteredSubscriber only receive the events of the
+// types they declared.
+//
+// Returns:
+//   - An error if a Subscriber with the same Uid is already subscribed.
+func (s *SyncStreamer) Subscribe(subscriber sirkeji.Subscriber) error {
+	s.dispatchMu.Lock()
+	for _, sub := range s.subscribers {
+		if sub.Uid() == subscriber.Uid() {
+			s.dispatchMu.Unlock()
+			return fmt.Errorf("subscriber %s already subscribed", subscriber.Uid())
+		}
+	}
+	s.subscribers = append(s.subscribers, subscriber)
+	s.dispatchMu.Unlock()
+
+	subscriber.Subscribed()
+	return nil
+}
+
+// Unsubscribe disconnects the Subscriber with the given Uid and calls its Unsubscribed method.
+func (s *SyncStreamer) Unsubscribe(subscriberUid string) {
+	s.dispatchMu.Lock()
+	var removed sirkeji.Subscriber
+	s.subscribers = slices.DeleteFunc(s.subscribers, func(sub sirkeji.Subscriber) bool {
+		if sub.Uid() == subscriberUid {
+			removed = sub
+			return true
+		}
+		return false
+	})
+	s.dispatchMu.Unlock()
+
+	if removed != nil {
+		removed.Unsubscribed()
+	}
+}
+
+// Publish records the event and delivers it to the interested Subscribers, in
+// subscription order.
+//
+// Events are stamped with an ID, the publish time and a CorrelationID when they miss
+// them, like sirkeji.DefaultStreamer does. Subscribers implementing sirkeji.ContextSubscriber
+// are called with ProcessContext and a context carrying the event, like a
+// sirkeji.SubscriptionManager does. Panics of Subscribers aren't recovered.
+func (s *SyncStreamer) Publish(event sirkeji.Event) {
+	event = stamp(event)
+	s.record(event)
+
+	s.dispatchMu.Lock()
+	s.queue = append(s.queue, event)
+	if s.dispatching {
+		s.dispatchMu.Unlock()
+		return
+	}
+	s.dispatching = true
+	s.dispatchMu.Unlock()
+
+	defer func() {
+		s.dispatchMu.Lock()
+		s.dispatching = false
+		s.queue = nil
+		s.dispatchMu.Unlock()
+	}()
+
+	for {
+		s.dispatchMu.Lock()
+		if len(s.queue) == 0 {
+			s.dispatchMu.Unlock()
+			return
+		}
+		next := s.queue[0]
+		s.queue = s.queue[1:]
+		subscribers := slices.Clone(s.subscribers)
+		s.dispatchMu.Unlock()
+
+		for _, subscriber := range subscribers {
+			if !accepts(subscriber, next.Type) {
+				continue
+			}
+			if ctxSub, ok := subscriber.(sirkeji.ContextSubscriber); ok {
+				ctxSub.ProcessContext(sirkeji.ContextWithCause(context.Background(), next), next)
+			} else {
+				subscriber.Process(next)
+			}
+		}
+	}
+}
+
+// accepts reports whether the Subscriber declared interest in the EventType.
+func accepts(subscriber sirkeji.Subscriber, eventType sirkeji.EventType) bool {
+	filtered, ok := subscriber.(sirkeji.FilteredSubscriber)
+	if !ok || len(filtered.EventTypes()) == 0 {
+		return true
+	}
+	for _, pattern := range filtered.EventTypes() {
+		if sirkeji.MatchEventType(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// stamp assigns an ID, a timestamp and a CorrelationID to an event missing them.
+func stamp(event sirkeji.Event) sirkeji.Event {
+	if event.ID == "" && event.Publisher != "" && event.Type != "" {
+		event.ID = sirkeji.NewEvent(event.Publisher, event.Type, event.Meta, event.Payload).ID
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = event.ID
+	}
+	return event
+}
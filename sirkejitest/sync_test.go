@@ -0,0 +1,133 @@
+package sirkejitest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thisiscetin/sirkeji"
+	"github.com/thisiscetin/sirkeji/example/numbers/events"
+	"github.com/thisiscetin/sirkeji/example/numbers/squared_number"
+	"github.com/thisiscetin/sirkeji/sirkejitest"
+)
+
+// collector is a Subscriber storing the events it processed.
+type collector struct {
+	uid        string
+	eventTypes []sirkeji.EventType
+	processed  []sirkeji.Event
+	subscribed bool
+}
+
+func (c *collector) Uid() string {
+	return c.uid
+}
+
+func (c *collector) EventTypes() []sirkeji.EventType {
+	return c.eventTypes
+}
+
+func (c *collector) Process(event sirkeji.Event) {
+	c.processed = append(c.processed, event)
+}
+
+func (c *collector) Subscribed() {
+	c.subscribed = true
+}
+
+func (c *collector) Unsubscribed() {
+	c.subscribed = false
+}
+
+// TestSyncStreamerProcessesInline ensures the whole event flow completed when Publish returns.
+func TestSyncStreamerProcessesInline(t *testing.T) {
+	streamer := sirkejitest.NewSyncStreamer()
+	if err := streamer.Subscribe(squared_number.NewPublisher("squarer", streamer.Publish)); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	streamer.Publish(sirkeji.NewEvent("test", events.Number.Type(), "", 3))
+
+	sirkejitest.AssertEvents(t, streamer, 0,
+		sirkeji.NewEvent("test", events.Number.Type(), "", 3),
+		sirkeji.NewEvent("squarer", events.SquaredNumber.Type(), "9", 9),
+	)
+}
+
+// TestSyncStreamerOrdering ensures events published during processing are delivered after
+// the event being processed, to every subscriber.
+func TestSyncStreamerOrdering(t *testing.T) {
+	streamer := sirkejitest.NewSyncStreamer()
+	_ = streamer.Subscribe(squared_number.NewPublisher("squarer", streamer.Publish))
+	all := &collector{uid: "all"}
+	squares := &collector{uid: "squares", eventTypes: []sirkeji.EventType{events.SquaredNumber.Type()}}
+	_ = streamer.Subscribe(all)
+	_ = streamer.Subscribe(squares)
+
+	streamer.Publish(sirkeji.NewEvent("test", events.Number.Type(), "", 2))
+
+	if len(all.processed) != 2 || all.processed[0].Type != events.Number.Type() || all.processed[1].Payload != 4 {
+		t.Errorf("Expected the number then its square, got %+v", all.processed)
+	}
+	if len(squares.processed) != 1 || squares.processed[0].Payload != 4 {
+		t.Errorf("Expected only the square to be routed to the filtered subscriber, got %+v", squares.processed)
+	}
+}
+
+// TestSyncStreamerSubscriptions ensures subscribers are notified and not subscribed twice.
+func TestSyncStreamerSubscriptions(t *testing.T) {
+	streamer := sirkejitest.NewSyncStreamer()
+	sub := &collector{uid: "collector"}
+
+	if err := streamer.Subscribe(sub); err != nil || !sub.subscribed {
+		t.Fatalf("Expected the subscriber to be subscribed, got %v", err)
+	}
+	if err := streamer.Subscribe(sub); err == nil {
+		t.Error("Expected an error subscribing the same subscriber twice")
+	}
+
+	streamer.Unsubscribe(sub.uid)
+	streamer.Publish(sirkeji.InfoEvent("test", "after unsubscribe"))
+
+	if sub.subscribed || len(sub.processed) != 0 {
+		t.Errorf("Expected the subscriber to be unsubscribed, processed %d events", len(sub.processed))
+	}
+}
+
+// contextCollector is a collector processing events with their context.
+type contextCollector struct {
+	collector
+	causes []sirkeji.Event
+}
+
+func (c *contextCollector) ProcessContext(ctx context.Context, event sirkeji.Event) {
+	cause, _ := sirkeji.CauseFromContext(ctx)
+	c.causes = append(c.causes, cause)
+	c.Process(event)
+}
+
+// TestSyncStreamerStamping ensures published events are stamped like sirkeji.DefaultStreamer
+// does and ContextSubscribers get the event being processed in their context.
+func TestSyncStreamerStamping(t *testing.T) {
+	streamer := sirkejitest.NewSyncStreamer()
+	sub := &contextCollector{collector: collector{uid: "context"}}
+	_ = streamer.Subscribe(sub)
+
+	event := sirkeji.NewEvent("test", events.Number.Type(), "", 1)
+	streamer.Publish(event)
+	streamer.Publish(sirkeji.Event{Publisher: "test", Type: sirkeji.Info})
+
+	if len(sub.processed) != 2 {
+		t.Fatalf("Expected 2 events to be processed, got %d", len(sub.processed))
+	}
+	for _, processed := range sub.processed {
+		if processed.ID == "" || processed.Time.IsZero() || processed.CorrelationID != processed.ID {
+			t.Errorf("Expected the event to be stamped, got %+v", processed)
+		}
+	}
+	if sub.processed[0].ID != event.ID {
+		t.Errorf("Expected the event to keep its ID %s, got %s", event.ID, sub.processed[0].ID)
+	}
+	if len(sub.causes) != 2 || sub.causes[0].ID != event.ID {
+		t.Errorf("Expected ProcessContext to be called with the event as cause, got %+v", sub.causes)
+	}
+}
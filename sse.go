@@ -0,0 +1,376 @@
+package sirkeji
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultSSEHistory is the number of recent events kept by SSEHandlers for clients
+	// resuming with a Last-Event-ID, see WithSSEHistory.
+	DefaultSSEHistory = 1000
+
+	// DefaultSSEQueueSize is the number of events queued for every client of an SSEHandler,
+	// see WithSSEQueueSize.
+	DefaultSSEQueueSize = 256
+
+	// DefaultSSEKeepAlive is the interval of the comments SSEHandlers send to idle clients,
+	// see WithSSEKeepAlive.
+	DefaultSSEKeepAlive = 15 * time.Second
+)
+
+// SSEHandler streams the events of a Streamer to HTTP clients as Server-Sent Events,
+// e.g. to watch them live from a browser with EventSource or from curl.
+//
+// Every client gets its own subscription, removed once the client disconnects. Events
+// are sent as JSON encoded data, with their ID as the SSE event ID:
+//
+//	id: 3f1c9e0a-...
+//	data: {"id":"3f1c9e0a-...","time":"...","publisher":"checkout","type":"orders.created","payload":{...}}
+//
+// Clients may filter the events with query parameters, each accepting several
+// comma-separated values:
+//   - type: The EventTypes to receive, which may be patterns like "orders.>" (see MatchEventType).
+//   - publisher: The publishers to receive events from.
+//
+// Clients reconnecting with a Last-Event-ID header first receive the events published
+// after that event, as long as it is still among the recent events kept by the handler.
+// Every event whose Publish returned before the client reconnected is resumed, the
+// handler waits for the recent events to be recorded first.
+type SSEHandler struct {
+	// streamer is the Streamer events are streamed from.
+	streamer Streamer
+	// uid identifies the handler's subscriptions, clients are subscribed as uid-1, uid-2, ...
+	uid string
+	// queueSize is the number of events queued for every client.
+	queueSize int
+	// keepAlive is the interval of the comments sent to idle clients.
+	keepAlive time.Duration
+	// historySize is the number of recent events kept for resuming clients.
+	historySize int
+
+	// history holds the recent events, nil if resuming is disabled.
+	history *eventHistory
+	// clients counts the clients ever connected, it numbers their subscriptions.
+	clients atomic.Uint64
+}
+
+// SSEOption configures an SSEHandler created by NewSSEHandler.
+type SSEOption func(*SSEHandler)
+
+// WithSSEHistory sets the number of recent events kept for clients resuming with a
+// Last-Event-ID, zero disabling resuming. DefaultSSEHistory is used without this option.
+//
+// Example:
+//
+//	handler, err := NewSSEHandler(streamer, "events-sse", WithSSEHistory(10000))
+func WithSSEHistory(size int) SSEOption {
+	return func(h *SSEHandler) {
+		h.historySize = size
+	}
+}
+
+// WithSSEQueueSize sets the number of events queued for every client. Slow clients lose
+// their oldest queued events rather than slowing down publishers. DefaultSSEQueueSize is
+// used without this option.
+func WithSSEQueueSize(size int) SSEOption {
+	return func(h *SSEHandler) {
+		h.queueSize = size
+	}
+}
+
+// WithSSEKeepAlive sets the interval of the comments sent to idle clients, so proxies
+// don't close their connections. DefaultSSEKeepAlive is used without this option.
+func WithSSEKeepAlive(interval time.Duration) SSEOption {
+	return func(h *SSEHandler) {
+		h.keepAlive = interval
+	}
+}
+
+// NewSSEHandler creates an SSEHandler streaming the events of the Streamer.
+//
+// Parameters:
+//   - streamer: The Streamer events are streamed from. Must not be nil.
+//   - uid: The unique identifier of the handler, prefixing the UIDs of its subscriptions.
+//   - opts: Optional SSEOptions.
+//
+// Returns:
+//   - A pointer to a new SSEHandler, keeping the recent events unless WithSSEHistory(0) is set.
+//   - An error if the streamer is nil or the history couldn't be subscribed (e.g., duplicate UID).
+//
+// Example:
+//
+//	handler, err := NewSSEHandler(streamer, "events-sse")
+//	if err != nil {
+//	    log.Fatalf("failed to create SSE handler: %v", err)
+//	}
+//	defer handler.Close()
+//
+//	http.Handle("/events", handler)
+//	// curl -N 'localhost:8080/events?type=orders.>&publisher=checkout'
+func NewSSEHandler(streamer Streamer, uid string, opts ...SSEOption) (*SSEHandler, error) {
+	if streamer == nil {
+		return nil, ErrStreamerShouldNotBeNil
+	}
+
+	h := &SSEHandler{
+		streamer:    streamer,
+		uid:         uid,
+		queueSize:   DefaultSSEQueueSize,
+		keepAlive:   DefaultSSEKeepAlive,
+		historySize: DefaultSSEHistory,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.historySize > 0 {
+		ch, err := streamer.Subscribe(uid+"-history", WithQueueSize(h.queueSize), WithOverflowPolicy(OverflowDropOldest))
+		if err != nil {
+			return nil, err
+		}
+		h.history = newEventHistory(h.historySize)
+		go h.history.record(ch)
+	}
+	return h, nil
+}
+
+// Close stops keeping the recent events. Connected clients keep receiving events until
+// they disconnect.
+func (h *SSEHandler) Close() {
+	if h.history != nil {
+		h.streamer.Unsubscribe(h.uid + "-history")
+	}
+}
+
+// sseFilter holds the filters requested by a client.
+type sseFilter struct {
+	types      []EventType
+	publishers []string
+}
+
+// newSSEFilter parses the filters of the request.
+func newSSEFilter(r *http.Request) sseFilter {
+	var f sseFilter
+	for _, t := range queryValues(r, "type") {
+		f.types = append(f.types, EventType(t))
+	}
+	f.publishers = queryValues(r, "publisher")
+	return f
+}
+
+// queryValues returns the non-empty values of the query parameter, splitting comma-separated values.
+func queryValues(r *http.Request, key string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[key] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// matches reports whether the event passes the filters.
+func (f sseFilter) matches(event Event) bool {
+	if len(f.publishers) > 0 && !slices.Contains(f.publishers, event.Publisher) {
+		return false
+	}
+	return len(f.types) == 0 || slices.ContainsFunc(f.types, func(pattern EventType) bool {
+		return MatchEventType(pattern, event.Type)
+	})
+}
+
+// ServeHTTP streams the events to the client until it disconnects or the subscription
+// is removed, e.g. by GracefulShutdown.
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter := newSSEFilter(r)
+	opts := []SubscribeOption{WithQueueSize(h.queueSize), WithOverflowPolicy(OverflowDropOldest)}
+	if len(filter.types) > 0 {
+		opts = append(opts, WithEventTypes(filter.types...))
+	}
+
+	uid := fmt.Sprintf("%s-%d", h.uid, h.clients.Add(1))
+	ch, err := h.streamer.Subscribe(uid, opts...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.streamer.Unsubscribe(uid)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Events replayed from the history may also be queued for the subscription.
+	replayed := make(map[string]bool)
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" && h.history != nil {
+		// The client is subscribed already, so the history only has to catch up with
+		// the events published before.
+		h.history.catchUp()
+		for _, event := range h.history.after(lastID) {
+			if !filter.matches(event) {
+				continue
+			}
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+			replayed[event.ID] = true
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if replayed[event.ID] || !filter.matches(event) {
+				continue
+			}
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE writes the event as a Server-Sent Event.
+func writeSSE(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(newEventJSON(event))
+	if err != nil {
+		return err
+	}
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+// eventHistory is a ring buffer of recent events.
+type eventHistory struct {
+	events []Event
+	// next is the index the next event is stored at.
+	next int
+	// full is set once the buffer wrapped around.
+	full bool
+	mu   sync.Mutex
+
+	// catchUps asks the recorder to store the events queued for it, it closes the
+	// given channel once done.
+	catchUps chan chan struct{}
+	// done is closed when the recorder stops.
+	done chan struct{}
+}
+
+// newEventHistory creates an eventHistory keeping the given number of events.
+func newEventHistory(size int) *eventHistory {
+	return &eventHistory{
+		events:   make([]Event, size),
+		catchUps: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// record adds the events received on the channel until it is closed.
+func (h *eventHistory) record(ch chan Event) {
+	defer close(h.done)
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.add(event)
+		case caughtUp := <-h.catchUps:
+			h.drain(ch)
+			close(caughtUp)
+		}
+	}
+}
+
+// drain adds the events already queued on the channel, without waiting for more.
+func (h *eventHistory) drain(ch chan Event) {
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.add(event)
+		default:
+			return
+		}
+	}
+}
+
+// catchUp waits for the recorder to store the events already queued for it.
+func (h *eventHistory) catchUp() {
+	caughtUp := make(chan struct{})
+	select {
+	case h.catchUps <- caughtUp:
+		<-caughtUp
+	case <-h.done:
+	}
+}
+
+// add stores the event, evicting the oldest event if the buffer is full.
+func (h *eventHistory) add(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// after returns the events stored after the event with the given ID, oldest first,
+// or nil if the event isn't stored anymore.
+func (h *eventHistory) after(id string) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := h.events[:h.next]
+	if h.full {
+		events = append(slices.Clone(h.events[h.next:]), events...)
+	}
+	for i, event := range events {
+		if event.ID == id {
+			return slices.Clone(events[i+1:])
+		}
+	}
+	return nil
+}
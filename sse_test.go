@@ -0,0 +1,259 @@
+package sirkeji
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseClient reads the Server-Sent Events streamed by an SSEHandler.
+type sseClient struct {
+	lines  chan string
+	cancel context.CancelFunc
+}
+
+func newSSEClient(t *testing.T, url string, lastEventID string) *sseClient {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	c := &sseClient{lines: make(chan string, 100), cancel: cancel}
+	go func() {
+		defer resp.Body.Close()
+		defer close(c.lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case c.lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	t.Cleanup(cancel)
+	return c
+}
+
+// next returns the ID and data of the next event, skipping comments.
+func (c *sseClient) next(t *testing.T) (string, eventJSON) {
+	t.Helper()
+
+	var (
+		id   string
+		data eventJSON
+	)
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data); err != nil {
+					t.Fatalf("invalid data %q: %v", line, err)
+				}
+			case line == "" && data.Type != "":
+				return id, data
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an event")
+		}
+	}
+}
+
+func newTestSSEHandler(t *testing.T, streamer Streamer, opts ...SSEOption) *SSEHandler {
+	t.Helper()
+
+	handler, err := NewSSEHandler(streamer, "sse", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(handler.Close)
+	return handler
+}
+
+// TestSSEHandlerStreamsEvents ensures clients receive the events matching their filters.
+func TestSSEHandlerStreamsEvents(t *testing.T) {
+	streamer := NewStreamer()
+	server := httptest.NewServer(newTestSSEHandler(t, streamer))
+	t.Cleanup(server.Close)
+
+	all := newSSEClient(t, server.URL, "")
+	filtered := newSSEClient(t, server.URL+"?type=orders.>,Error&publisher=checkout", "")
+
+	order := codecTestOrder{ID: "order-1", Items: []string{"tea"}, Total: 1.5}
+	events := []Event{
+		InfoEvent("checkout", "not an order"),
+		ErrorEvent("inventory", "other publisher"),
+		NewEvent("checkout", "orders.created", "", nil),
+		codecTestJSONOrder.New("checkout", "", order),
+		ErrorEvent("checkout", "payment failed"),
+	}
+	for _, event := range events {
+		streamer.Publish(event)
+	}
+
+	for _, event := range events {
+		id, data := all.next(t)
+		if id != event.ID || data.ID != event.ID || data.Type != event.Type || data.Publisher != event.Publisher {
+			t.Errorf("expected %s %s, got %s %+v", event.ID, event.Type, id, data)
+		}
+	}
+
+	for _, expected := range []EventType{"orders.created", Error} {
+		if _, data := filtered.next(t); data.Type != expected {
+			t.Errorf("expected %s, got %s", expected, data.Type)
+		}
+	}
+}
+
+// TestSSEHandlerPayload ensures payloads are embedded as JSON.
+func TestSSEHandlerPayload(t *testing.T) {
+	streamer := NewStreamer()
+	server := httptest.NewServer(newTestSSEHandler(t, streamer))
+	t.Cleanup(server.Close)
+
+	client := newSSEClient(t, server.URL, "")
+	streamer.Publish(codecTestJSONOrder.New("checkout", "", codecTestOrder{ID: "order-1", Items: []string{"tea"}, Total: 1.5}))
+
+	_, data := client.next(t)
+	if expected := `{"ID":"order-1","Items":["tea"],"Total":1.5}`; string(data.Payload) != expected {
+		t.Errorf("expected payload %s, got %s", expected, data.Payload)
+	}
+}
+
+// TestSSEHandlerResume ensures clients reconnecting with a Last-Event-ID receive the
+// events they missed, once, before the live events.
+func TestSSEHandlerResume(t *testing.T) {
+	streamer := NewStreamer()
+	handler := newTestSSEHandler(t, streamer, WithSSEHistory(3))
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var published []Event
+	for i := 0; i < 4; i++ {
+		event := InfoEvent("test", string(rune('a'+i)))
+		published = append(published, event)
+		streamer.Publish(event)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(handler.history.after(published[1].ID)) != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	client := newSSEClient(t, server.URL, published[1].ID)
+	live := InfoEvent("test", "live")
+	streamer.Publish(live)
+
+	for _, expected := range []Event{published[2], published[3], live} {
+		if id, _ := client.next(t); id != expected.ID {
+			t.Errorf("expected %s, got %s", expected.Meta, id)
+		}
+	}
+
+	if missed := handler.history.after(published[0].ID); missed != nil {
+		t.Errorf("expected evicted events not to be resumed from, got %d events", len(missed))
+	}
+}
+
+// TestSSEHandlerResumeGap ensures clients reconnecting right after events were published
+// receive them, even if the history didn't record them yet.
+func TestSSEHandlerResumeGap(t *testing.T) {
+	streamer := NewStreamer()
+	server := httptest.NewServer(newTestSSEHandler(t, streamer))
+	t.Cleanup(server.Close)
+
+	var published []Event
+	for i := 0; i < 200; i++ {
+		event := InfoEvent("test", "resumed")
+		published = append(published, event)
+		streamer.Publish(event)
+	}
+
+	client := newSSEClient(t, server.URL, published[0].ID)
+	for _, expected := range published[1:] {
+		if id, _ := client.next(t); id != expected.ID {
+			t.Fatalf("expected %s, got %s", expected.ID, id)
+		}
+	}
+}
+
+// TestEventHistoryCatchUp ensures the history stores the events queued for it before
+// being read by a resuming client.
+func TestEventHistoryCatchUp(t *testing.T) {
+	history := newEventHistory(10)
+	ch := make(chan Event, 10)
+	first, second := InfoEvent("test", "first"), InfoEvent("test", "second")
+	ch <- first
+	ch <- second
+	go history.record(ch)
+
+	history.catchUp()
+	if events := history.after(first.ID); len(events) != 1 || events[0].ID != second.ID {
+		t.Errorf("expected the queued event to be recorded, got %v", events)
+	}
+
+	close(ch)
+	<-history.done
+	history.catchUp()
+}
+
+// TestSSEHandlerKeepAlive ensures idle clients receive keep-alive comments.
+func TestSSEHandlerKeepAlive(t *testing.T) {
+	server := httptest.NewServer(newTestSSEHandler(t, NewStreamer(), WithSSEKeepAlive(10*time.Millisecond)))
+	t.Cleanup(server.Close)
+
+	client := newSSEClient(t, server.URL, "")
+	select {
+	case line := <-client.lines:
+		if line != ": keep-alive" {
+			t.Errorf("expected a keep-alive comment, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a keep-alive comment")
+	}
+}
+
+// TestSSEHandlerUnsubscribes ensures clients are unsubscribed once they disconnect.
+func TestSSEHandlerUnsubscribes(t *testing.T) {
+	streamer := NewStreamer()
+	server := httptest.NewServer(newTestSSEHandler(t, streamer, WithSSEHistory(0)))
+	t.Cleanup(server.Close)
+
+	client := newSSEClient(t, server.URL, "")
+	if _, err := streamer.Subscribe("sse-1"); err == nil {
+		t.Fatal("expected the client to be subscribed as sse-1")
+	}
+
+	client.cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := streamer.Subscribe("sse-1"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the client to be unsubscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
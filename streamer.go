@@ -1,8 +1,12 @@
 package sirkeji
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
+	"sync/atomic"
 )
 
 // Streamer defines the interface for an event stream.
@@ -11,11 +15,12 @@ type Streamer interface {
 	// Subscribe connects a subscriber to the Streamer and returns a channel for receiving events.
 	// Parameters:
 	//   - subscriberUid: A unique identifier for the subscriber.
+	//   - opts: Optional settings for the subscriber's queue, such as its size and overflow policy.
 	//
 	// Returns:
 	//   - A channel for receiving events.
 	//   - An error if the subscriberUid is already subscribed.
-	Subscribe(subscriberUid string) (chan Event, error)
+	Subscribe(subscriberUid string, opts ...SubscribeOption) (chan Event, error)
 
 	// Unsubscribe removes a subscriber from the Streamer and closes its event channel.
 	// Parameters:
@@ -26,31 +31,118 @@ type Streamer interface {
 	// Parameters:
 	//   - event: The Event to be published.
 	Publish(event Event)
+
+	// PublishContext broadcasts an event to all connected subscribers, giving up when the context is done.
+	// Parameters:
+	//   - ctx: Bounds the time spent waiting for subscribers.
+	//   - event: The Event to be published.
+	//   - opts: Optional PublishOptions, e.g. WithPublishMode.
+	//
+	// Returns:
+	//   - A *DeliveryError listing the subscribers that didn't receive the event, or nil.
+	//
+	// Events published with a context carrying the event being processed (see ContextWithCause)
+	// are linked to it; from a plain Subscriber's Process, link events with Event.Derive instead.
+	PublishContext(ctx context.Context, event Event, opts ...PublishOption) error
 }
 
 // DefaultStreamer is the default implementation of the Streamer interface.
 // It manages subscribers and broadcasts events to all active channels.
+//
+// Every subscriber gets its own bounded queue, so a slow subscriber only affects
+// publishers according to the OverflowPolicy it was subscribed with.
 type DefaultStreamer struct {
-	// subscribers holds a map of subscriber IDs to their event channels.
-	subscribers map[string]chan Event
+	// subscribers holds a map of subscriber IDs to their subscriptions.
+	subscribers map[string]*subscription
+	// unfiltered holds the subscriptions receiving every event.
+	unfiltered map[string]*subscription
+	// topics indexes the filtered subscriptions by the EventType patterns they declared.
+	topics *topicTrie
+	// groups holds the consumer groups by name, see WithGroup.
+	groups map[string]*consumerGroup
+	// onOverflow is notified about events that couldn't be queued for a subscriber.
+	onOverflow OverflowHandler
+	// eventLog records every published event when set, see WithEventLog.
+	eventLog *EventLog
+	// publishMiddlewares wrap every publish, see WithPublishMiddleware.
+	publishMiddlewares []PublishMiddleware
+	// metrics receives the measurements of the streamer, see WithMetrics.
+	metrics Metrics
+	// stopped is set once the streamer stops accepting events, see StopPublishing.
+	stopped atomic.Bool
+	// subscriptions counts the subscriptions ever made, it orders them.
+	subscriptions uint64
 	// RWMutex ensures thread-safe access to the subscribers map.
 	sync.RWMutex
 }
 
+// StreamerOption configures a DefaultStreamer created by NewStreamer.
+type StreamerOption func(*DefaultStreamer)
+
+// WithOverflowHandler registers a handler notified whenever an event is dropped or
+// rejected by a subscriber's queue.
+//
+// The handler is called synchronously from Publish and must not block.
+//
+// Example:
+//
+//	streamer := NewStreamer(WithOverflowHandler(func(uid string, event Event, err error) {
+//	    log.Printf("[%s] missed %s: %v", uid, event.Type, err)
+//	}))
+func WithOverflowHandler(handler OverflowHandler) StreamerOption {
+	return func(s *DefaultStreamer) {
+		s.onOverflow = handler
+	}
+}
+
+// WithEventLog records every published event in the given EventLog.
+//
+// Published events are assigned the offset of their log record, and subscribers may
+// replay the logged history with FromOffset or FromBeginning.
+//
+// Events are appended while the streamer's read lock is held, so a replaying subscriber
+// sees every event exactly once, either replayed or live. Publishers don't wait for each
+// other on it, but Subscribe and Unsubscribe wait for the appends in progress, including
+// their disk writes and the syncs of WithSyncOnAppend.
+//
+// Example:
+//
+//	eventLog, err := OpenEventLog("data/events")
+//	if err != nil {
+//	    log.Fatalf("failed to open event log: %v", err)
+//	}
+//	streamer := NewStreamer(WithEventLog(eventLog))
+func WithEventLog(eventLog *EventLog) StreamerOption {
+	return func(s *DefaultStreamer) {
+		s.eventLog = eventLog
+	}
+}
+
 // NewStreamer creates and returns a new instance of DefaultStreamer.
 //
+// Parameters:
+//   - opts: Optional StreamerOptions.
+//
 // Returns:
 //   - A pointer to a new DefaultStreamer.
-func NewStreamer() *DefaultStreamer {
-	return &DefaultStreamer{
-		subscribers: make(map[string]chan Event),
+func NewStreamer(opts ...StreamerOption) *DefaultStreamer {
+	s := &DefaultStreamer{
+		subscribers: make(map[string]*subscription),
+		unfiltered:  make(map[string]*subscription),
+		topics:      newTopicTrie(),
+		groups:      make(map[string]*consumerGroup),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Subscribe connects a subscriber to the DefaultStreamer and returns its event channel.
 //
 // Parameters:
 //   - subscriberUid: A unique identifier for the subscriber.
+//   - opts: Optional SubscribeOptions configuring the subscriber's queue.
 //
 // Returns:
 //   - A channel for receiving events.
@@ -58,16 +150,23 @@ func NewStreamer() *DefaultStreamer {
 //
 // Behavior:
 //   - If the subscriberUid is already in use, an error is returned.
-//   - A new channel is created for the subscriber and added to the subscribers map.
+//   - A new buffered channel is created for the subscriber and added to the subscribers map.
+//   - Without options the queue holds DefaultQueueSize events and uses OverflowBlock.
+//   - With WithEventTypes only events of the declared types, or matching the declared
+//     wildcard patterns, are routed to the subscriber.
+//   - With FromOffset or FromBeginning the logged history is queued before any live event.
+//   - With WithGroup the subscriber shares the events routed to its consumer group with
+//     the other members, an error wrapping ErrGroupStrategyMismatch is returned if it
+//     uses another strategy than them.
 //
 // Example:
 //
 //	streamer := NewStreamer()
-//	ch, err := streamer.Subscribe("user123")
+//	ch, err := streamer.Subscribe("user123", WithQueueSize(16), WithOverflowPolicy(OverflowDropOldest))
 //	if err != nil {
 //	    log.Fatalf("failed to subscribe: %v", err)
 //	}
-func (s *DefaultStreamer) Subscribe(subscriberUid string) (chan Event, error) {
+func (s *DefaultStreamer) Subscribe(subscriberUid string, opts ...SubscribeOption) (chan Event, error) {
 	s.Lock()
 	defer s.Unlock()
 
@@ -75,9 +174,68 @@ func (s *DefaultStreamer) Subscribe(subscriberUid string) (chan Event, error) {
 		return nil, fmt.Errorf("subscriber %s already subscribed", subscriberUid)
 	}
 
-	ch := make(chan Event)
-	s.subscribers[subscriberUid] = ch
-	return ch, nil
+	sub := newSubscription(subscriberUid, newSubscribeConfig(opts), s.overflowHandler())
+	if sub.replayFrom > 0 && s.eventLog == nil {
+		return nil, ErrNoEventLog
+	}
+	if sub.group != "" {
+		if err := s.join(sub); err != nil {
+			return nil, err
+		}
+	}
+	if sub.replayFrom > 0 {
+		// Publishers append to the log while holding the read lock, so no event
+		// can slip between the end of the replay and the first live event.
+		sub.replaying.Store(true)
+		go sub.replay(s.eventLog, sub.replayFrom, s.eventLog.NextOffset())
+	}
+
+	s.subscriptions++
+	sub.seq = s.subscriptions
+	s.subscribers[subscriberUid] = sub
+	s.index(sub)
+	return sub.ch, nil
+}
+
+// overflowHandler returns the OverflowHandler of new subscriptions, counting the
+// overflown events when metrics are enabled.
+func (s *DefaultStreamer) overflowHandler() OverflowHandler {
+	if s.metrics == nil {
+		return s.onOverflow
+	}
+	return func(uid string, event Event, err error) {
+		s.metrics.EventDropped(uid, event.Type, err)
+		if s.onOverflow != nil {
+			s.onOverflow(uid, event, err)
+		}
+	}
+}
+
+// processMetrics returns the Metrics SubscriptionManagers report their measurements to.
+func (s *DefaultStreamer) processMetrics() Metrics {
+	return s.metrics
+}
+
+// index adds the subscription to the routing tables. The caller must hold the lock.
+func (s *DefaultStreamer) index(sub *subscription) {
+	if len(sub.eventTypes) == 0 {
+		s.unfiltered[sub.uid] = sub
+		return
+	}
+	for _, pattern := range sub.eventTypes {
+		s.topics.insert(pattern, sub)
+	}
+}
+
+// unindex removes the subscription from the routing tables. The caller must hold the lock.
+func (s *DefaultStreamer) unindex(sub *subscription) {
+	delete(s.unfiltered, sub.uid)
+	for _, pattern := range sub.eventTypes {
+		s.topics.remove(pattern, sub)
+	}
+	if sub.group != "" {
+		s.leave(sub)
+	}
 }
 
 // Unsubscribe removes a subscriber from the DefaultStreamer and closes its event channel.
@@ -86,8 +244,10 @@ func (s *DefaultStreamer) Subscribe(subscriberUid string) (chan Event, error) {
 //   - subscriberUid: The unique identifier of the subscriber to remove.
 //
 // Behavior:
-//   - Closes the subscriber's event channel.
+//   - Releases publishers blocked on the subscriber's queue.
+//   - Closes the subscriber's event channel, events already queued can still be received.
 //   - Removes the subscriberUid from the subscribers map.
+//   - Tells the Metrics the subscriber was removed, see WithMetrics.
 //   - If the subscriberUid is not found, no action is taken.
 //
 // Example:
@@ -96,12 +256,19 @@ func (s *DefaultStreamer) Subscribe(subscriberUid string) (chan Event, error) {
 //	streamer.Unsubscribe("user123")
 func (s *DefaultStreamer) Unsubscribe(subscriberUid string) {
 	s.Lock()
-	defer s.Unlock()
-
-	if ch, ok := s.subscribers[subscriberUid]; ok {
-		close(ch)
+	sub, ok := s.subscribers[subscriberUid]
+	if ok {
+		s.unindex(sub)
 	}
 	delete(s.subscribers, subscriberUid)
+	s.Unlock()
+
+	if ok {
+		sub.close()
+		if s.metrics != nil {
+			s.metrics.SubscriberRemoved(subscriberUid)
+		}
+	}
 }
 
 // Publish broadcasts an event to all connected subscribers.
@@ -110,8 +277,16 @@ func (s *DefaultStreamer) Unsubscribe(subscriberUid string) {
 //   - event: The Event to be published.
 //
 // Behavior:
-//   - Sends the event to all active subscriber channels.
-//   - If a channel is blocked or slow, the operation may pause.
+//   - Assigns an ID, the publish time and a CorrelationID to the event if it has none.
+//   - Passes the event through the PublishMiddlewares, which may modify or reject it.
+//   - Appends the event to the EventLog, if one is attached, before queueing it. An event
+//     the EventLog fails to append is logged and still queued. The append happens under
+//     the streamer's read lock, see WithEventLog.
+//   - Queues the event for all active subscribers interested in its type.
+//   - The subscribers map is not locked while queueing, so subscribing and unsubscribing
+//     are never held up by a slow subscriber.
+//   - A full queue is handled according to the subscriber's OverflowPolicy, only
+//     subscribers using OverflowBlock or OverflowBlockTimeout may pause the operation.
 //
 // Example:
 //
@@ -119,10 +294,165 @@ func (s *DefaultStreamer) Unsubscribe(subscriberUid string) {
 //	event := Event{Publisher: "system", Type: Info, Meta: "App started"}
 //	streamer.Publish(event)
 func (s *DefaultStreamer) Publish(event Event) {
+	_ = s.PublishContext(context.Background(), event)
+}
+
+// PublishContext broadcasts an event to all connected subscribers, giving up when the context is done.
+//
+// Parameters:
+//   - ctx: Bounds the time spent waiting for subscribers with full queues.
+//   - event: The Event to be published.
+//   - opts: Optional PublishOptions, e.g. WithPublishMode(FireAndForget).
+//
+// Returns:
+//   - nil if the event was queued for every interested subscriber.
+//   - A *DeliveryError listing the subscribers that didn't receive the event and why.
+//   - An error wrapping the EventLog failure if the event couldn't be logged; the event
+//     is still delivered in that case.
+//
+// Behavior:
+//   - Behaves like Publish, but stops waiting for subscribers once the context is done;
+//     the remaining subscribers are reported with the context's error.
+//   - Events published with a context carrying the event being processed, like the one
+//     given to ContextSubscribers, inherit its CorrelationID and record its ID as their
+//     CausationID (see ContextWithCause). Only ContextSubscribers get such a context;
+//     plain Subscribers link the events they publish from Process with Event.Derive,
+//     a NewEvent published without it starts a new chain.
+//   - Subscribers with full queues are waited for concurrently, so one slow subscriber
+//     doesn't delay delivery to the others.
+//   - Events dropped by OverflowDropNewest, rejected by OverflowError or timed out by
+//     OverflowBlockTimeout are reported as undelivered.
+//   - With FireAndForget, subscribers that can't queue the event immediately are skipped.
+//   - Once StopPublishing was called, events are rejected with ErrStreamerStopped.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+//	defer cancel()
+//
+//	var deliveryErr *DeliveryError
+//	if err := streamer.PublishContext(ctx, event); errors.As(err, &deliveryErr) {
+//	    log.Printf("not delivered to %v", deliveryErr.Undelivered())
+//	}
+func (s *DefaultStreamer) PublishContext(ctx context.Context, event Event, opts ...PublishOption) error {
+	if s.stopped.Load() {
+		return ErrStreamerStopped
+	}
+
+	cfg := newPublishConfig(opts)
+	publish := func(ctx context.Context, event Event) error {
+		return s.publish(ctx, event, cfg)
+	}
+	return chainPublish(publish, s.publishMiddlewares)(ctx, stampEvent(inheritCause(ctx, event)))
+}
+
+// StopPublishing makes the DefaultStreamer reject every subsequent event with ErrStreamerStopped.
+//
+// Subscribers stay connected and keep receiving the events already queued for them.
+// It is called by GracefulShutdown before draining the subscribers.
+//
+// Example:
+//
+//	streamer.StopPublishing()
+//	err := streamer.PublishContext(ctx, event) // ErrStreamerStopped
+func (s *DefaultStreamer) StopPublishing() {
+	s.stopped.Store(true)
+}
+
+// publish logs the event and queues it for the interested subscribers.
+func (s *DefaultStreamer) publish(ctx context.Context, event Event, cfg publishConfig) error {
+	wait := cfg.mode == WaitForEnqueue
+
 	s.RLock()
-	defer s.RUnlock()
+	var logErr error
+	if s.eventLog != nil {
+		event.Offset, logErr = s.eventLog.Append(event)
+	}
+	if logErr != nil {
+		log.Printf("[%s] failed to append %s event %s to the event log: %v\n", event.Publisher, event.Type, event.ID, logErr)
+	}
+	subs := s.balance(event, s.route(event.Type))
+	s.RUnlock()
+
+	if s.metrics != nil {
+		s.metrics.EventPublished(event.Type)
+	}
+
+	var (
+		failures  map[string]error
+		delivered atomic.Int64
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+	)
+	fail := func(sub *subscription, err error) {
+		if err == nil {
+			delivered.Add(1)
+		}
+		if err == nil && s.metrics != nil {
+			s.metrics.EventDelivered(sub.uid)
+			s.metrics.QueueDepth(sub.uid, len(sub.ch))
+		}
+		if err == nil || errors.Is(err, ErrSubscriberClosed) {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
 
-	for _, subscriber := range s.subscribers {
-		subscriber <- event
+		if failures == nil {
+			failures = make(map[string]error)
+		}
+		failures[sub.uid] = err
+	}
+
+	for _, sub := range subs {
+		if err := ctx.Err(); err != nil {
+			fail(sub, err)
+			continue
+		}
+		if !wait || !sub.blocking() {
+			fail(sub, sub.deliver(ctx, event, wait))
+			continue
+		}
+		if sub.offer(event) {
+			fail(sub, nil)
+			continue
+		}
+		// Subscribers that make us wait are served concurrently, so a single slow
+		// subscriber can't use up the context for everyone else.
+		wg.Add(1)
+		go func(sub *subscription) {
+			defer wg.Done()
+			fail(sub, sub.deliver(ctx, event, wait))
+		}(sub)
+	}
+	wg.Wait()
+
+	if cfg.delivered != nil {
+		*cfg.delivered = int(delivered.Load())
+	}
+	var deliveryErr error
+	if failures != nil {
+		deliveryErr = &DeliveryError{Event: event, Failures: failures}
+	}
+	if logErr != nil {
+		return errors.Join(fmt.Errorf("append to event log: %w", logErr), deliveryErr)
+	}
+	return deliveryErr
+}
+
+// route returns the active subscriptions interested in the given EventType.
+// The caller must hold the lock.
+func (s *DefaultStreamer) route(eventType EventType) []*subscription {
+	// A subscription may declare several patterns matching the same EventType.
+	matched := make(map[string]*subscription)
+	s.topics.match(eventType, matched)
+
+	subs := make([]*subscription, 0, len(s.unfiltered)+len(matched))
+	for _, sub := range s.unfiltered {
+		subs = append(subs, sub)
+	}
+	for _, sub := range matched {
+		subs = append(subs, sub)
 	}
+	return subs
 }
@@ -1,6 +1,7 @@
 package sirkeji
 
 import (
+	"reflect"
 	"sync"
 	"testing"
 )
@@ -60,7 +61,8 @@ func TestPublish(t *testing.T) {
 		ch1, _ := streamer.Subscribe("user1")
 		ch2, _ := streamer.Subscribe("user2")
 
-		event := Event{Publisher: "system", Type: Info, Meta: "Test Event"}
+		// Stamped up front like Publish does, so the received copies are identical.
+		event := stampEvent(NewEvent("system", Info, "Test Event", nil))
 
 		wg := sync.WaitGroup{}
 		wg.Add(2)
@@ -68,7 +70,7 @@ func TestPublish(t *testing.T) {
 		go func() {
 			// Verify the event is received by both subscribers
 			received1 := <-ch1
-			if received1 != event {
+			if !reflect.DeepEqual(received1, event) {
 				t.Errorf("expected event %+v, got %+v", event, received1)
 				return
 			}
@@ -77,7 +79,7 @@ func TestPublish(t *testing.T) {
 
 		go func() {
 			received2 := <-ch2
-			if received2 != event {
+			if !reflect.DeepEqual(received2, event) {
 				t.Errorf("expected event %+v, got %+v", event, received2)
 				return
 			}
@@ -119,3 +121,35 @@ func TestConcurrentAccess(t *testing.T) {
 		streamer.Publish(event)
 	}()
 }
+
+// TestPublishFilteredByType ensures events are only routed to subscribers interested in their type.
+func TestPublishFilteredByType(t *testing.T) {
+	streamer := NewStreamer()
+
+	all, _ := streamer.Subscribe("all")
+	errorsOnly, _ := streamer.Subscribe("errors-only", WithEventTypes(Error))
+	shutdownOnly, _ := streamer.Subscribe("shutdown-only", WithEventTypes(Shutdown))
+
+	streamer.Publish(Event{Publisher: "system", Type: Info, Meta: "info"})
+	streamer.Publish(Event{Publisher: "system", Type: Error, Meta: "error"})
+
+	if len(all) != 2 {
+		t.Errorf("expected 2 events for the unfiltered subscriber, got %d", len(all))
+	}
+	if len(errorsOnly) != 1 {
+		t.Fatalf("expected 1 event for the Error subscriber, got %d", len(errorsOnly))
+	}
+	if event := <-errorsOnly; event.Type != Error {
+		t.Errorf("expected an Error event, got %s", event.Type)
+	}
+	if len(shutdownOnly) != 0 {
+		t.Errorf("expected no events for the Shutdown subscriber, got %d", len(shutdownOnly))
+	}
+
+	streamer.Unsubscribe("errors-only")
+	streamer.Publish(Event{Publisher: "system", Type: Error, Meta: "error"})
+
+	if _, ok := <-errorsOnly; ok {
+		t.Error("expected the Error subscriber channel to be closed")
+	}
+}
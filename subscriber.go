@@ -1,6 +1,7 @@
 package sirkeji
 
 import (
+	"context"
 	"errors"
 	"log"
 )
@@ -36,6 +37,46 @@ type Subscriber interface {
 	Unsubscribed()
 }
 
+// FilteredSubscriber is an optional interface for Subscribers interested only in
+// specific EventTypes.
+//
+// When a Subscriber implements it, the SubscriptionManager subscribes it with the
+// declared types and the Streamer routes only matching events to it, so Process
+// never has to check the type of the event itself.
+type FilteredSubscriber interface {
+	Subscriber
+
+	// EventTypes returns the EventTypes the subscriber wants to receive.
+	//
+	// Types may be hierarchical patterns with wildcards, e.g. "orders.*", see
+	// MatchEventType. Returning no types subscribes to every event.
+	EventTypes() []EventType
+}
+
+// ContextSubscriber is an optional interface for Subscribers publishing events in
+// reaction to the events they process.
+//
+// When a Subscriber implements it, the SubscriptionManager calls ProcessContext rather
+// than Process, with a context carrying the event (see ContextWithCause). Events
+// published with PublishContext and that context automatically inherit the
+// CorrelationID of the processed event and record its ID as their CausationID.
+//
+// Example:
+//
+//	func (b *Biller) ProcessContext(ctx context.Context, event sirkeji.Event) {
+//	    _ = b.streamer.PublishContext(ctx, sirkeji.NewEvent(b.uid, InvoiceCreated, "", invoice(event)))
+//	}
+type ContextSubscriber interface {
+	Subscriber
+
+	// ProcessContext handles the received event, like Process.
+	//
+	// Parameters:
+	//   - ctx: A context carrying the event, to publish the events caused by it with.
+	//   - event: The Event instance to be processed by the subscriber.
+	ProcessContext(ctx context.Context, event Event)
+}
+
 // SubscriptionManager manages the lifecycle of a Subscriber with a Streamer.
 //
 // It provides methods to connect and disconnect a Subscriber, handling the
@@ -47,6 +88,49 @@ type SubscriptionManager struct {
 
 	// subscriber is the Subscriber instance receiving events from the Streamer.
 	subscriber Subscriber
+
+	// subscribeOpts are passed to the Streamer when subscribing.
+	subscribeOpts []SubscribeOption
+
+	// mode selects how events are handed over to the Subscriber, see ProcessingMode.
+	mode ProcessingMode
+	// workers is the worker count of the ProcessPooled and ProcessKeyed modes.
+	workers int
+	// keyFunc extracts ordering keys in the ProcessKeyed mode.
+	keyFunc KeyFunc
+
+	// onPanic handles panics recovered from Process, see WithPanicHandler.
+	onPanic PanicHandler
+
+	// processMiddlewares wrap the Subscriber's Process method, see WithProcessMiddleware.
+	processMiddlewares []ProcessMiddleware
+
+	// retryPolicy retries the events an ErrorProcessor failed to process, see WithRetry.
+	retryPolicy RetryPolicy
+	// onDeadLetter handles the events that couldn't be processed, see WithDeadLetterHandler.
+	onDeadLetter DeadLetterHandler
+
+	// metrics receives the processing measurements, see WithProcessMetrics.
+	metrics Metrics
+
+	// clock tells the time of the manager and of ClockedSubscribers, see WithClock.
+	clock Clock
+}
+
+// ManagerOption configures a SubscriptionManager created by NewSubscriptionManager.
+type ManagerOption func(*SubscriptionManager)
+
+// WithSubscribeOptions passes SubscribeOptions, such as the queue size and overflow
+// policy, to the Streamer when the managed Subscriber is subscribed.
+//
+// Example:
+//
+//	manager, err := NewSubscriptionManager(streamer, subscriber,
+//	    WithSubscribeOptions(WithQueueSize(256), WithOverflowPolicy(OverflowDropOldest)))
+func WithSubscribeOptions(opts ...SubscribeOption) ManagerOption {
+	return func(sm *SubscriptionManager) {
+		sm.subscribeOpts = append(sm.subscribeOpts, opts...)
+	}
 }
 
 var (
@@ -62,6 +146,7 @@ var (
 // Parameters:
 //   - streamer: The Streamer instance managing event delivery. Must not be nil.
 //   - subscriber: The Subscriber instance to manage. Must not be nil.
+//   - opts: Optional ManagerOptions.
 //
 // Returns:
 //   - A pointer to a new SubscriptionManager instance.
@@ -80,7 +165,7 @@ var (
 //	if err != nil {
 //	    log.Fatalf("Failed to create SubscriptionManager: %v", err)
 //	}
-func NewSubscriptionManager(streamer Streamer, subscriber Subscriber) (*SubscriptionManager, error) {
+func NewSubscriptionManager(streamer Streamer, subscriber Subscriber, opts ...ManagerOption) (*SubscriptionManager, error) {
 	if streamer == nil {
 		return nil, ErrStreamerShouldNotBeNil
 	}
@@ -88,16 +173,22 @@ func NewSubscriptionManager(streamer Streamer, subscriber Subscriber) (*Subscrip
 		return nil, ErrSubscriberShouldNotBeNil
 	}
 
-	return &SubscriptionManager{
+	sm := &SubscriptionManager{
 		streamer:   streamer,
 		subscriber: subscriber,
-	}, nil
+		clock:      SystemClock,
+	}
+	for _, opt := range opts {
+		opt(sm)
+	}
+	return sm, nil
 }
 
 // Subscribe connects the subscriber to the Streamer and starts processing events.
 //
 // This method subscribes the Subscriber to the Streamer and spawns a goroutine
-// to process incoming events by invoking the Subscriber's Process method.
+// to process incoming events by invoking the Subscriber's Process method according
+// to the manager's ProcessingMode.
 //
 // Parameters:
 //   - None.
@@ -106,8 +197,21 @@ func NewSubscriptionManager(streamer Streamer, subscriber Subscriber) (*Subscrip
 //   - An error if the subscription fails (e.g., duplicate subscriber UID).
 //
 // Behavior:
+//   - Restricts the subscription to the declared types if the Subscriber is a FilteredSubscriber.
+//   - Calls ProcessContext rather than Process if the Subscriber is a ContextSubscriber.
 //   - Starts a goroutine to listen for events and route them to the Subscriber's Process method.
-//   - Calls the Subscriber's Subscribed method upon successful subscription.
+//   - By default every event is processed in its own goroutine, use WithSequentialProcessing,
+//     WithWorkerPool or WithKeyedWorkerPool for ordered or bounded processing.
+//   - Wraps Process with the ProcessMiddlewares, see WithProcessMiddleware.
+//   - Recovers panics raised by Process and publishes them as Error events, see WithPanicHandler.
+//   - Retries the events an ErrorProcessor fails to process and publishes those still
+//     failing as DeadLetter events, see WithRetry and WithDeadLetterHandler.
+//   - Reports processing latencies, queue depths and panics to the Metrics of the manager
+//     or of the streamer, see WithProcessMetrics and WithMetrics.
+//   - Hands the manager's Clock to ClockedSubscribers before subscribing, see WithClock.
+//   - Calls the Subscriber's Subscribed method upon successful subscription, before any
+//     event is processed.
+//   - Registers the manager with the Streamer's shutdown coordinator, see GracefulShutdown.
 //
 // Example:
 //
@@ -117,19 +221,55 @@ func NewSubscriptionManager(streamer Streamer, subscriber Subscriber) (*Subscrip
 //	    log.Fatalf("failed to subscribe: %v", err)
 //	}
 func (sm *SubscriptionManager) Subscribe() error {
-	ch, err := sm.streamer.Subscribe(sm.subscriber.Uid())
+	opts := sm.subscribeOpts
+	if filtered, ok := sm.subscriber.(FilteredSubscriber); ok {
+		opts = append(opts[:len(opts):len(opts)], WithEventTypes(filtered.EventTypes()...))
+	}
+	if clocked, ok := sm.subscriber.(ClockedSubscriber); ok {
+		clocked.SetClock(sm.clock)
+	}
+
+	ch, err := sm.streamer.Subscribe(sm.subscriber.Uid(), opts...)
 	if err != nil {
 		return err
 	}
 
-	go func(ch chan Event) {
-		for event := range ch {
-			go sm.subscriber.Process(event)
-		}
-	}(ch)
+	var sub *subscription
+	if registry, ok := sm.streamer.(managerRegistry); ok {
+		sub = registry.registerManager(sm)
+	}
 
+	process := sm.subscriber.Process
+	if ctxSub, ok := sm.subscriber.(ContextSubscriber); ok {
+		process = func(event Event) {
+			ctxSub.ProcessContext(ContextWithCause(context.Background(), event), event)
+		}
+	}
+	if errSub, ok := sm.subscriber.(ErrorSubscriber); ok {
+		process = sm.withRetries(errSub.ProcessErr)
+	}
+	process = sm.withRecovery(chainProcess(process, sm.processMiddlewares))
+	sm.metrics = sm.resolveMetrics()
+	if sm.metrics != nil {
+		process = sm.measure(sm.metrics, process)
+	}
+	process = track(process, sub)
 	sm.subscriber.Subscribed()
 
+	d := newDispatcher(sm.mode, sm.workers, sm.keyFunc, process)
+	go func(ch chan Event, metrics Metrics) {
+		for event := range ch {
+			if metrics != nil {
+				metrics.QueueDepth(sm.subscriber.Uid(), len(ch))
+			}
+			d.dispatch(event)
+		}
+		d.stop()
+		if metrics != nil {
+			metrics.SubscriberRemoved(sm.subscriber.Uid())
+		}
+	}(ch, sm.metrics)
+
 	log.Printf("[%s] subscribed to the streamer\n", sm.subscriber.Uid())
 	return nil
 }
@@ -2,6 +2,7 @@ package sirkeji
 
 import (
 	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -55,6 +56,16 @@ func (ms *MockSubscriber) GetProcessedEvents() []Event {
 	return ms.processed
 }
 
+// MockFilteredSubscriber is a MockSubscriber declaring the EventTypes it is interested in.
+type MockFilteredSubscriber struct {
+	*MockSubscriber
+	eventTypes []EventType
+}
+
+func (mfs *MockFilteredSubscriber) EventTypes() []EventType {
+	return mfs.eventTypes
+}
+
 // TestNewSubscriptionManager verifies the behavior of NewSubscriptionManager.
 func TestNewSubscriptionManager(t *testing.T) {
 	streamer := NewStreamer()
@@ -111,7 +122,7 @@ func TestSubscriptionManagerSubscribe(t *testing.T) {
 	}
 
 	// Verify that the subscriber receives published events
-	event := Event{Publisher: "system", Type: Info, Meta: "Test Event"}
+	event := stampEvent(NewEvent("system", Info, "Test Event", nil))
 	streamer.Publish(event)
 
 	time.Sleep(100 * time.Millisecond) // Allow time for the event to propagate
@@ -120,7 +131,7 @@ func TestSubscriptionManagerSubscribe(t *testing.T) {
 	if len(processedEvents) != 1 {
 		t.Fatalf("expected 1 processed event, got %d", len(processedEvents))
 	}
-	if processedEvents[0] != event {
+	if !reflect.DeepEqual(processedEvents[0], event) {
 		t.Errorf("expected event %+v, got %+v", event, processedEvents[0])
 	}
 }
@@ -153,3 +164,32 @@ func TestSubscriptionManagerUnsubscribe(t *testing.T) {
 		t.Fatalf("expected 0 processed events, got %d", len(processedEvents))
 	}
 }
+
+// TestSubscriptionManagerFilteredSubscriber verifies a FilteredSubscriber only processes declared types.
+func TestSubscriptionManagerFilteredSubscriber(t *testing.T) {
+	streamer := NewStreamer()
+	subscriber := &MockFilteredSubscriber{
+		MockSubscriber: NewMockSubscriber("filtered-subscriber"),
+		eventTypes:     []EventType{Error},
+	}
+	manager, err := NewSubscriptionManager(streamer, subscriber)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.Subscribe(); err != nil {
+		t.Fatalf("unexpected error during subscription: %v", err)
+	}
+
+	streamer.Publish(InfoEvent("system", "ignored"))
+	streamer.Publish(ErrorEvent("system", "processed"))
+
+	time.Sleep(100 * time.Millisecond) // Allow time for events to propagate
+
+	processedEvents := subscriber.GetProcessedEvents()
+	if len(processedEvents) != 1 {
+		t.Fatalf("expected 1 processed event, got %d", len(processedEvents))
+	}
+	if processedEvents[0].Type != Error {
+		t.Errorf("expected an Error event, got %s", processedEvents[0].Type)
+	}
+}
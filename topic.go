@@ -0,0 +1,135 @@
+package sirkeji
+
+import "strings"
+
+const (
+	// TopicSeparator separates the levels of hierarchical EventTypes, e.g. "orders.created".
+	TopicSeparator = "."
+
+	// WildcardOne matches exactly one level of an EventType, e.g. "orders.*" matches
+	// "orders.created" but neither "orders" nor "orders.created.eu".
+	WildcardOne = "*"
+
+	// WildcardMany, as the last level of a pattern, matches one or more levels of an
+	// EventType, e.g. "orders.>" matches "orders.created" and "orders.created.eu".
+	WildcardMany = ">"
+)
+
+// MatchEventType reports whether the EventType matches the subscription pattern.
+//
+// Patterns are EventTypes whose levels may be WildcardOne, or WildcardMany for the last
+// level. A pattern without wildcards only matches the identical EventType.
+//
+// Example:
+//
+//	MatchEventType("orders.*", "orders.created")     // true
+//	MatchEventType("orders.>", "orders.created.eu")  // true
+//	MatchEventType("orders.*", "orders.created.eu")  // false
+func MatchEventType(pattern, eventType EventType) bool {
+	patternLevels := strings.Split(string(pattern), TopicSeparator)
+	levels := strings.Split(string(eventType), TopicSeparator)
+
+	for i, p := range patternLevels {
+		if p == WildcardMany && i == len(patternLevels)-1 {
+			return len(levels) > i
+		}
+		if i >= len(levels) || (p != WildcardOne && p != levels[i]) {
+			return false
+		}
+	}
+	return len(levels) == len(patternLevels)
+}
+
+// topicTrie indexes subscriptions by the levels of their EventType patterns, so the
+// subscriptions interested in an EventType are found without checking every pattern.
+type topicTrie struct {
+	root *topicNode
+}
+
+// topicNode is a level of the topicTrie.
+type topicNode struct {
+	// children holds the next levels, wildcards included, by name.
+	children map[string]*topicNode
+	// subs holds the subscriptions whose pattern ends at this level.
+	subs map[string]*subscription
+	// many holds the subscriptions whose pattern ends with WildcardMany after this level.
+	many map[string]*subscription
+}
+
+func newTopicTrie() *topicTrie {
+	return &topicTrie{root: &topicNode{}}
+}
+
+// insert indexes the subscription under the pattern.
+func (t *topicTrie) insert(pattern EventType, sub *subscription) {
+	levels := strings.Split(string(pattern), TopicSeparator)
+	node := t.root
+	for i, level := range levels {
+		if level == WildcardMany && i == len(levels)-1 {
+			if node.many == nil {
+				node.many = make(map[string]*subscription)
+			}
+			node.many[sub.uid] = sub
+			return
+		}
+
+		child, ok := node.children[level]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[string]*topicNode)
+			}
+			child = &topicNode{}
+			node.children[level] = child
+		}
+		node = child
+	}
+
+	if node.subs == nil {
+		node.subs = make(map[string]*subscription)
+	}
+	node.subs[sub.uid] = sub
+}
+
+// remove drops the subscription from the pattern, pruning the levels left empty.
+func (t *topicTrie) remove(pattern EventType, sub *subscription) {
+	t.root.remove(strings.Split(string(pattern), TopicSeparator), sub.uid)
+}
+
+// remove drops the subscription from the remaining levels and reports whether the node is empty.
+func (n *topicNode) remove(levels []string, uid string) bool {
+	switch {
+	case len(levels) == 1 && levels[0] == WildcardMany:
+		delete(n.many, uid)
+	case len(levels) == 0:
+		delete(n.subs, uid)
+	default:
+		if child, ok := n.children[levels[0]]; ok && child.remove(levels[1:], uid) {
+			delete(n.children, levels[0])
+		}
+	}
+	return len(n.children) == 0 && len(n.subs) == 0 && len(n.many) == 0
+}
+
+// match collects the subscriptions with a pattern matching the EventType into matched.
+func (t *topicTrie) match(eventType EventType, matched map[string]*subscription) {
+	t.root.match(strings.Split(string(eventType), TopicSeparator), matched)
+}
+
+func (n *topicNode) match(levels []string, matched map[string]*subscription) {
+	if len(levels) == 0 {
+		for uid, sub := range n.subs {
+			matched[uid] = sub
+		}
+		return
+	}
+
+	for uid, sub := range n.many {
+		matched[uid] = sub
+	}
+	if child, ok := n.children[levels[0]]; ok {
+		child.match(levels[1:], matched)
+	}
+	if child, ok := n.children[WildcardOne]; ok {
+		child.match(levels[1:], matched)
+	}
+}
@@ -0,0 +1,80 @@
+package sirkeji
+
+import (
+	"context"
+	"testing"
+)
+
+// TestMatchEventType ensures wildcard patterns match the expected EventTypes.
+func TestMatchEventType(t *testing.T) {
+	tests := []struct {
+		pattern   EventType
+		eventType EventType
+		want      bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.created", "orders.cancelled", false},
+		{"orders", "orders.created", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.created.eu", false},
+		{"*.created", "orders.created", true},
+		{"*.created", "payments.created", true},
+		{"orders.>", "orders.created", true},
+		{"orders.>", "orders.created.eu", true},
+		{"orders.>", "orders", false},
+		{"orders.*.eu", "orders.created.eu", true},
+		{">", "Info", true},
+		{"*", "Info", true},
+		{"*", "orders.created", false},
+		{"orders.>.eu", "orders.>.eu", true},
+		{"orders.>.eu", "orders.created.eu", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchEventType(tt.pattern, tt.eventType); got != tt.want {
+			t.Errorf("MatchEventType(%q, %q) = %v, expected %v", tt.pattern, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+// TestWildcardRouting ensures the streamer routes events to subscriptions with matching patterns.
+func TestWildcardRouting(t *testing.T) {
+	streamer := NewStreamer()
+	orders, _ := streamer.Subscribe("orders", WithEventTypes("orders.*"))
+	everything, _ := streamer.Subscribe("everything", WithEventTypes("orders.>", "orders.created"))
+	created, _ := streamer.Subscribe("created", WithEventTypes("*.created"))
+
+	for _, eventType := range []EventType{"orders.created", "orders.created.eu", "orders.cancelled", "payments.created"} {
+		if err := streamer.PublishContext(context.Background(), NewEvent("test", eventType, "", nil)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]struct {
+		ch   chan Event
+		want []EventType
+	}{
+		"orders":     {orders, []EventType{"orders.created", "orders.cancelled"}},
+		"everything": {everything, []EventType{"orders.created", "orders.created.eu", "orders.cancelled"}},
+		"created":    {created, []EventType{"orders.created", "payments.created"}},
+	}
+	for uid, e := range expected {
+		if len(e.ch) != len(e.want) {
+			t.Errorf("[%s] expected %d events, got %d", uid, len(e.want), len(e.ch))
+			continue
+		}
+		for _, want := range e.want {
+			if event := <-e.ch; event.Type != want {
+				t.Errorf("[%s] expected %s, got %s", uid, want, event.Type)
+			}
+		}
+	}
+
+	streamer.Unsubscribe("orders")
+	streamer.Unsubscribe("everything")
+	streamer.Unsubscribe("created")
+	if len(streamer.topics.root.children) != 0 || len(streamer.topics.root.many) != 0 {
+		t.Errorf("expected the topic trie to be pruned, got %+v", streamer.topics.root)
+	}
+}
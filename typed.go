@@ -0,0 +1,176 @@
+package sirkeji
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedEvent binds an EventType to the Go type of its Payload.
+//
+// Creating and reading events through a TypedEvent moves payload type checks to
+// compile time for publishers, and turns failed assertions into errors for subscribers.
+type TypedEvent[T any] struct {
+	eventType EventType
+}
+
+// DefineEvent registers a new EventType carrying payloads of type T.
+//
+// Payloads are encoded with JSONCodec unless another Codec is set with WithCodec.
+//
+// Panics if the EventType is already registered, see RegisterEventType.
+//
+// Parameters:
+//   - eventType: The EventType to register.
+//   - opts: Optional EventTypeOptions, e.g. WithCodec(GobCodec).
+//
+// Returns:
+//   - A TypedEvent for creating and reading events of the given type.
+//
+// Example:
+//
+//	var NumberEvent = sirkeji.DefineEvent[int]("Number")
+func DefineEvent[T any](eventType EventType, opts ...EventTypeOption) TypedEvent[T] {
+	opts = append([]EventTypeOption{WithCodec(JSONCodec)}, opts...)
+	RegisterEventType(eventType, append(opts, withPayloadType(reflect.TypeFor[T]()))...)
+	return TypedEvent[T]{eventType: eventType}
+}
+
+// Type returns the EventType of the definition.
+func (d TypedEvent[T]) Type() EventType {
+	return d.eventType
+}
+
+// New creates a new Event of the definition's type with a payload of type T.
+//
+// Example:
+//
+//	event := NumberEvent.New("number-publisher", "42", 42)
+func (d TypedEvent[T]) New(publisher, meta string, payload T) Event {
+	return NewEvent(publisher, d.eventType, meta, payload)
+}
+
+// Payload extracts the typed payload of the event.
+//
+// Returns:
+//   - The payload asserted to T.
+//   - A *PayloadTypeError if the event has a different type or its payload isn't a T.
+//
+// Example:
+//
+//	n, err := NumberEvent.Payload(event)
+func (d TypedEvent[T]) Payload(event Event) (T, error) {
+	payload, ok := event.Payload.(T)
+	if !ok || event.Type != d.eventType {
+		var zero T
+		return zero, &PayloadTypeError{
+			EventType: d.eventType,
+			Event:     event,
+			Expected:  reflect.TypeFor[T]().String(),
+			Actual:    fmt.Sprintf("%T", event.Payload),
+		}
+	}
+	return payload, nil
+}
+
+// PayloadTypeError is reported when an event doesn't match its TypedEvent definition.
+//
+// Fields:
+//   - EventType: The EventType of the TypedEvent definition.
+//   - Event: The mismatching Event.
+//   - Expected: The name of the expected payload type.
+//   - Actual: The name of the payload type found on the event.
+type PayloadTypeError struct {
+	EventType EventType
+	Event     Event
+	Expected  string
+	Actual    string
+}
+
+// Error implements the error interface.
+func (e *PayloadTypeError) Error() string {
+	if e.Event.Type != e.EventType {
+		return fmt.Sprintf("expected %s event, got %s", e.EventType, e.Event.Type)
+	}
+	return fmt.Sprintf("%s event payload should be %s, got %s", e.EventType, e.Expected, e.Actual)
+}
+
+// Publish publishes an event of the given definition with a payload of type T.
+//
+// Parameters:
+//   - streamer: The Streamer to publish the event to.
+//   - definition: The TypedEvent describing the event.
+//   - publisher: The origin of the event.
+//   - meta: Optional metadata describing the event.
+//   - payload: The payload, checked against the definition at compile time.
+//
+// Example:
+//
+//	sirkeji.Publish(streamer, NumberEvent, "number-publisher", "42", 42)
+func Publish[T any](streamer Streamer, definition TypedEvent[T], publisher, meta string, payload T) {
+	streamer.Publish(definition.New(publisher, meta, payload))
+}
+
+// TypedHandler is a Subscriber adaptor delivering already asserted payloads of a
+// single TypedEvent to a handler function. It is created by Handle.
+type TypedHandler[T any] struct {
+	uid        string
+	definition TypedEvent[T]
+	publish    func(Event)
+	handler    func(event Event, payload T)
+}
+
+// Handle creates a Subscriber processing events of the given definition.
+//
+// The handler is only called with events whose payload is a T. Mismatching events
+// are reported as Error events caused by the offending event, carrying a
+// *PayloadTypeError as their Payload.
+//
+// Parameters:
+//   - uid: The unique identifier of the Subscriber.
+//   - definition: The TypedEvent to subscribe to.
+//   - publish: The function used to report mismatches, typically Streamer.Publish.
+//   - handler: Called with each event and its typed payload.
+//
+// Returns:
+//   - A TypedHandler, which is a FilteredSubscriber for the definition's EventType.
+//
+// Example:
+//
+//	sirkeji.Subscribe(streamer, sirkeji.Handle("squarer", NumberEvent, streamer.Publish,
+//	    func(event sirkeji.Event, n int) {
+//	        sirkeji.Publish(streamer, SquaredNumberEvent, "squarer", "", n*n)
+//	    }))
+func Handle[T any](uid string, definition TypedEvent[T], publish func(Event), handler func(event Event, payload T)) *TypedHandler[T] {
+	return &TypedHandler[T]{
+		uid:        uid,
+		definition: definition,
+		publish:    publish,
+		handler:    handler,
+	}
+}
+
+// Uid returns the unique identifier of the TypedHandler.
+func (h *TypedHandler[T]) Uid() string {
+	return h.uid
+}
+
+// EventTypes returns the EventType of the handled definition.
+func (h *TypedHandler[T]) EventTypes() []EventType {
+	return []EventType{h.definition.Type()}
+}
+
+// Process asserts the payload of the event and passes it to the handler.
+func (h *TypedHandler[T]) Process(event Event) {
+	payload, err := h.definition.Payload(event)
+	if err != nil {
+		h.publish(event.Derive(h.uid, Error, err.Error(), err))
+		return
+	}
+	h.handler(event, payload)
+}
+
+// Subscribed is a no-op.
+func (h *TypedHandler[T]) Subscribed() {}
+
+// Unsubscribed is a no-op.
+func (h *TypedHandler[T]) Unsubscribed() {}
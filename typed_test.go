@@ -0,0 +1,100 @@
+package sirkeji
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	testNumberEvent = DefineEvent[int]("TypedTestNumber")
+	testTextEvent   = DefineEvent[string]("TypedTestText")
+)
+
+// TestTypedEventPayload ensures TypedEvent creates events and asserts their payloads.
+func TestTypedEventPayload(t *testing.T) {
+	t.Run("Registers the EventType", func(t *testing.T) {
+		if !IsEventTypeRegistered(testNumberEvent.Type()) {
+			t.Errorf("expected EventType '%s' to be registered", testNumberEvent.Type())
+		}
+	})
+
+	t.Run("Matching payload", func(t *testing.T) {
+		event := testNumberEvent.New("test-publisher", "42", 42)
+
+		n, err := testNumberEvent.Payload(event)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 42 {
+			t.Errorf("expected payload 42, got %d", n)
+		}
+	})
+
+	t.Run("Mismatching payload", func(t *testing.T) {
+		event := NewEvent("test-publisher", testNumberEvent.Type(), "", "42")
+
+		_, err := testNumberEvent.Payload(event)
+		var typeErr *PayloadTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected a *PayloadTypeError, got %v", err)
+		}
+		if typeErr.Expected != "int" || typeErr.Actual != "string" {
+			t.Errorf("expected int/string mismatch, got %s/%s", typeErr.Expected, typeErr.Actual)
+		}
+	})
+
+	t.Run("Mismatching type", func(t *testing.T) {
+		event := testTextEvent.New("test-publisher", "", "text")
+
+		if _, err := testNumberEvent.Payload(event); err == nil {
+			t.Fatal("expected an error for an event of another type")
+		}
+	})
+}
+
+// TestHandle ensures the typed adaptor delivers asserted payloads and reports mismatches.
+func TestHandle(t *testing.T) {
+	streamer := NewStreamer()
+	errorsCh, _ := streamer.Subscribe("error-watcher", WithEventTypes(Error))
+
+	received := make(chan int, 1)
+	subscribeWith(t, streamer, Handle("typed-handler", testNumberEvent, streamer.Publish,
+		func(event Event, n int) {
+			received <- n
+		}))
+
+	Publish(streamer, testNumberEvent, "test-publisher", "7", 7)
+	Publish(streamer, testTextEvent, "test-publisher", "ignored", "ignored")
+
+	select {
+	case n := <-received:
+		if n != 7 {
+			t.Errorf("expected payload 7, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the handler to be called")
+	}
+
+	bad := NewEvent("test-publisher", testNumberEvent.Type(), "", "seven")
+	streamer.Publish(bad)
+
+	select {
+	case reported := <-errorsCh:
+		if reported.Publisher != "typed-handler" {
+			t.Errorf("expected Publisher 'typed-handler', got '%s'", reported.Publisher)
+		}
+		if reported.CausationID != bad.ID {
+			t.Errorf("expected CausationID '%s', got '%s'", bad.ID, reported.CausationID)
+		}
+		if _, ok := reported.Payload.(*PayloadTypeError); !ok {
+			t.Errorf("expected a *PayloadTypeError payload, got %T", reported.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an Error event for the mismatching payload")
+	}
+
+	if len(received) != 0 {
+		t.Error("expected the handler not to be called for the mismatching payload")
+	}
+}
@@ -0,0 +1,285 @@
+package sirkeji
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the Sec-WebSocket-Key of handshakes, see RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes, see RFC 6455 section 5.2.
+const (
+	wsContinuation byte = 0x0
+	wsText         byte = 0x1
+	wsBinary       byte = 0x2
+	wsClose        byte = 0x8
+	wsPing         byte = 0x9
+	wsPong         byte = 0xa
+)
+
+// WebSocket close status codes, see RFC 6455 section 7.4.1.
+const (
+	wsCloseNormal        uint16 = 1000
+	wsCloseProtocolError uint16 = 1002
+	wsCloseTooBig        uint16 = 1009
+)
+
+var (
+	// ErrWebSocketHandshake is returned when an HTTP request isn't a valid WebSocket handshake.
+	ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+
+	// ErrWebSocketProtocol is returned when a WebSocket peer breaks the framing rules of RFC 6455.
+	ErrWebSocketProtocol = errors.New("websocket protocol error")
+
+	// ErrMessageTooLarge is returned when a WebSocket message exceeds the maximum message size.
+	ErrMessageTooLarge = errors.New("websocket message too large")
+
+	// errWebSocketClosed is returned when reading from a connection closed by the peer.
+	errWebSocketClosed = errors.New("websocket closed")
+)
+
+// wsConn is a WebSocket connection, implementing the framing of RFC 6455.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	// client masks the written frames and expects unmasked frames, as WebSocket clients do.
+	client bool
+	// maxMessageSize bounds the size of the messages read, zero for no bound.
+	maxMessageSize int64
+	// writeTimeout bounds the time spent writing a frame, zero for no bound.
+	writeTimeout time.Duration
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept value answering the handshake key.
+func websocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains reports whether the comma-separated header holds the token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptWebSocket completes the server side of a WebSocket handshake and takes over the
+// connection. Invalid handshakes are answered with an HTTP error.
+func acceptWebSocket(w http.ResponseWriter, r *http.Request, maxMessageSize int64, writeTimeout time.Duration) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	switch {
+	case r.Method != http.MethodGet,
+		!headerContains(r.Header, "Connection", "upgrade"),
+		!headerContains(r.Header, "Upgrade", "websocket"),
+		err != nil || len(decoded) != 16:
+		http.Error(w, ErrWebSocketHandshake.Error(), http.StatusBadRequest)
+		return nil, ErrWebSocketHandshake
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, ErrWebSocketHandshake
+	}
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, err
+	}
+	_, err = fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err == nil {
+		err = brw.Flush()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: brw.Reader, maxMessageSize: maxMessageSize, writeTimeout: writeTimeout}, nil
+}
+
+// readMessage reads the next text or binary message, reassembling fragmented messages
+// and answering the control frames received in between.
+func (c *wsConn) readMessage() (byte, []byte, error) {
+	var (
+		opcode  byte
+		message []byte
+	)
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			code := wsCloseNormal
+			if len(payload) >= 2 {
+				code = binary.BigEndian.Uint16(payload)
+			}
+			c.close(code, "")
+			return 0, nil, errWebSocketClosed
+		case wsText, wsBinary:
+			if opcode != 0 {
+				return 0, nil, fmt.Errorf("%w: new message within a fragmented message", ErrWebSocketProtocol)
+			}
+			opcode = op
+		case wsContinuation:
+			if opcode == 0 {
+				return 0, nil, fmt.Errorf("%w: continuation without a message", ErrWebSocketProtocol)
+			}
+		default:
+			return 0, nil, fmt.Errorf("%w: unknown opcode %#x", ErrWebSocketProtocol, op)
+		}
+
+		if c.maxMessageSize > 0 && int64(len(message)+len(payload)) > c.maxMessageSize {
+			return 0, nil, ErrMessageTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking its payload.
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op := header[0]&0x80 != 0, header[0]&0x0f
+	masked, length := header[1]&0x80 != 0, uint64(header[1]&0x7f)
+
+	switch {
+	case header[0]&0x70 != 0:
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", ErrWebSocketProtocol)
+	case masked == c.client:
+		return false, 0, nil, fmt.Errorf("%w: unexpected masking", ErrWebSocketProtocol)
+	case op >= wsClose && (!fin || length > 125):
+		return false, 0, nil, fmt.Errorf("%w: invalid control frame", ErrWebSocketProtocol)
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if c.maxMessageSize > 0 && length > uint64(c.maxMessageSize) {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes a single, final frame, masking its payload on the client side.
+// The connection is closed if the frame can't be written within the write timeout.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|op)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		_, _ = rand.Read(mask[:])
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.writeTimeout > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		// The frame may be partly written, e.g. to a peer that stopped reading, so
+		// nothing can be written after it anymore.
+		_ = c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// writeText writes a text message.
+func (c *wsConn) writeText(data []byte) error {
+	return c.writeFrame(wsText, data)
+}
+
+// close sends a close frame with the status code and reason, then closes the connection.
+func (c *wsConn) close(code uint16, reason string) {
+	c.closeOnce.Do(func() {
+		payload := binary.BigEndian.AppendUint16(nil, code)
+		_ = c.writeFrame(wsClose, append(payload, reason...))
+		_ = c.conn.Close()
+	})
+}
@@ -0,0 +1,204 @@
+package sirkeji
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newWSPipe returns the server and client ends of an in-memory WebSocket connection.
+func newWSPipe(maxMessageSize int64) (*wsConn, *wsConn) {
+	server, client := net.Pipe()
+	return &wsConn{conn: server, br: bufio.NewReader(server), maxMessageSize: maxMessageSize},
+		&wsConn{conn: client, br: bufio.NewReader(client), client: true}
+}
+
+// clientFrame returns a masked frame, as sent by clients.
+func clientFrame(fin bool, op byte, payload []byte) []byte {
+	first := op
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first, 0x80 | byte(len(payload)), 1, 2, 3, 4}
+	for i, b := range payload {
+		frame = append(frame, b^frame[2+i%4])
+	}
+	return frame
+}
+
+// TestWebSocketAccept ensures handshake keys are answered as in RFC 6455 section 1.3.
+func TestWebSocketAccept(t *testing.T) {
+	if accept := websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("expected s3pPLMBiTxaQ9kYGzzhZRbK+xOo=, got %s", accept)
+	}
+}
+
+// TestWebSocketHandshakeRejected ensures invalid handshakes are answered with HTTP errors.
+func TestWebSocketHandshakeRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		key      string
+		expected int
+	}{
+		{"missing key", "13", "", http.StatusBadRequest},
+		{"invalid key", "13", "not base64", http.StatusBadRequest},
+		{"unsupported version", "8", "dGhlIHNhbXBsZSBub25jZQ==", http.StatusUpgradeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Connection", "keep-alive, Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", tt.version)
+			req.Header.Set("Sec-WebSocket-Key", tt.key)
+			rec := httptest.NewRecorder()
+
+			if _, err := acceptWebSocket(rec, req, 0, 0); !errors.Is(err, ErrWebSocketHandshake) {
+				t.Errorf("expected ErrWebSocketHandshake, got %v", err)
+			}
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+// TestWebSocketMessages ensures messages of every length encoding survive the framing both ways.
+func TestWebSocketMessages(t *testing.T) {
+	server, client := newWSPipe(0)
+	defer server.conn.Close()
+	defer client.conn.Close()
+
+	for _, size := range []int{0, 125, 126, 70000} {
+		message := bytes.Repeat([]byte("a"), size)
+
+		go func() { _ = client.writeText(message) }()
+		op, data, err := server.readMessage()
+		if err != nil || op != wsText || !bytes.Equal(data, message) {
+			t.Errorf("expected a %d byte text message from the client, got %d bytes, %#x and %v", size, len(data), op, err)
+		}
+
+		go func() { _ = server.writeText(message) }()
+		op, data, err = client.readMessage()
+		if err != nil || op != wsText || !bytes.Equal(data, message) {
+			t.Errorf("expected a %d byte text message from the server, got %d bytes, %#x and %v", size, len(data), op, err)
+		}
+	}
+}
+
+// TestWebSocketFragments ensures fragmented messages are reassembled and pings answered in between.
+func TestWebSocketFragments(t *testing.T) {
+	server, client := newWSPipe(0)
+	defer server.conn.Close()
+	defer client.conn.Close()
+
+	go func() {
+		_, _ = client.conn.Write(clientFrame(false, wsText, []byte("hello, ")))
+		_, _ = client.conn.Write(clientFrame(true, wsPing, []byte("ping")))
+		_, _ = client.conn.Write(clientFrame(true, wsContinuation, []byte("world")))
+	}()
+	pong := make(chan []byte, 1)
+	go func() {
+		_, op, payload, _ := client.readFrame()
+		if op == wsPong {
+			pong <- payload
+		}
+		close(pong)
+	}()
+
+	op, data, err := server.readMessage()
+	if err != nil || op != wsText || string(data) != "hello, world" {
+		t.Errorf("expected the reassembled message, got %q, %#x and %v", data, op, err)
+	}
+	if payload := <-pong; string(payload) != "ping" {
+		t.Errorf("expected the ping to be answered, got %q", payload)
+	}
+}
+
+// TestWebSocketProtocolErrors ensures frames breaking the protocol are rejected.
+func TestWebSocketProtocolErrors(t *testing.T) {
+	unmasked := []byte{0x81, 0x02, 'h', 'i'}
+
+	tests := []struct {
+		name     string
+		frames   [][]byte
+		expected error
+	}{
+		{"unmasked", [][]byte{unmasked}, ErrWebSocketProtocol},
+		{"reserved bits", [][]byte{{0xc1, 0x80, 0, 0, 0, 0}}, ErrWebSocketProtocol},
+		{"fragmented ping", [][]byte{clientFrame(false, wsPing, nil)}, ErrWebSocketProtocol},
+		{"orphan continuation", [][]byte{clientFrame(true, wsContinuation, []byte("hi"))}, ErrWebSocketProtocol},
+		{"interleaved messages", [][]byte{clientFrame(false, wsText, []byte("a")), clientFrame(true, wsText, []byte("b"))}, ErrWebSocketProtocol},
+		{"too large", [][]byte{clientFrame(true, wsText, []byte(strings.Repeat("a", 20)))}, ErrMessageTooLarge},
+		{"too large once reassembled", [][]byte{clientFrame(false, wsText, []byte("0123456789")), clientFrame(true, wsContinuation, []byte("0123456789"))}, ErrMessageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := newWSPipe(16)
+			defer server.conn.Close()
+			defer client.conn.Close()
+
+			go func() {
+				for _, frame := range tt.frames {
+					if _, err := client.conn.Write(frame); err != nil {
+						return
+					}
+				}
+			}()
+			if _, _, err := server.readMessage(); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestWebSocketClose ensures close frames are echoed before the connection is closed.
+func TestWebSocketClose(t *testing.T) {
+	server, client := newWSPipe(0)
+	defer client.conn.Close()
+
+	go func() {
+		_, _ = client.conn.Write(clientFrame(true, wsClose, binary.BigEndian.AppendUint16(nil, 4000)))
+	}()
+	echoed := make(chan uint16, 1)
+	go func() {
+		_, op, payload, err := client.readFrame()
+		if err == nil && op == wsClose && len(payload) >= 2 {
+			echoed <- binary.BigEndian.Uint16(payload)
+		}
+		close(echoed)
+	}()
+
+	if _, _, err := server.readMessage(); !errors.Is(err, errWebSocketClosed) {
+		t.Errorf("expected errWebSocketClosed, got %v", err)
+	}
+	if code := <-echoed; code != 4000 {
+		t.Errorf("expected the close code 4000 to be echoed, got %d", code)
+	}
+}
+
+// TestWebSocketWriteTimeout ensures connections are closed once a frame can't be written in time.
+func TestWebSocketWriteTimeout(t *testing.T) {
+	server, client := newWSPipe(0)
+	defer client.conn.Close()
+	server.writeTimeout = 20 * time.Millisecond
+
+	if err := server.writeText([]byte("nobody reads")); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expected the write to time out, got %v", err)
+	}
+	if err := server.writeText([]byte("closed")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected the connection to be closed, got %v", err)
+	}
+}